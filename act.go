@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// Fetches issues by key, reporting the ones that don't exist.
+// Fails if none of them exist.
+func fetchIssuesReportMissing(fetcher *IssueFetcher, keys []string) ([]jira.Issue, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("No issue keys given")
+	}
+
+	issues, missing, err := fetcher.FetchByKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range missing {
+		fmt.Printf("Issue does not exist: %s\n", key)
+	}
+	if len(issues) == 0 {
+		return nil, errors.New("None of the issues exist")
+	}
+	return issues, nil
+}
+
+// Prints every issue identified by keys
+func RunGet(app *App, keys []string) error {
+	issues, err := fetchIssuesReportMissing(app.issueFetcher, keys)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Printf("%s: %s\n", issue.Key, issue.Fields.Summary)
+	}
+	return nil
+}
+
+// Runs a single action non-interactively against the issues identified by keys.
+// A preview is shown and confirmed first, unless assumeYes is set
+func RunAct(app *App, actionKey string, params []string, keys []string, assumeYes bool) error {
+	actionBase, err := app.actionBaseService.BuildActionParams(actionKey, params)
+	if err != nil {
+		return err
+	}
+
+	issues, err := fetchIssuesReportMissing(app.issueFetcher, keys)
+	if err != nil {
+		return err
+	}
+
+	actions := make([]IssueAction, len(issues))
+	for i, issue := range issues {
+		actions[i] = IssueAction{issue, actionBase}
+	}
+
+	return confirmAndExecute(app.executorService, app.menuService, actions, assumeYes)
+}
+
+// Previews the actions and asks for confirmation unless assumeYes is set,
+// then executes them, failing if any of them failed
+func confirmAndExecute(executorService *ExecutorService, menuService *MenuService, actions []IssueAction, assumeYes bool) error {
+	if !assumeYes {
+		executorService.Execute(actions, true)
+		if !menuService.Confirm(fmt.Sprintf("Execute %d action(s)", len(actions))) {
+			return CancelError()
+		}
+	}
+
+	failed, notAttempted := 0, 0
+	for _, err := range executorService.Execute(actions, false) {
+		switch err {
+		case nil:
+		case errNotAttempted:
+			notAttempted++
+		default:
+			failed++
+		}
+	}
+	// Aborted as a whole, e.g. by the safety check, rather than failing item by item
+	if notAttempted == len(actions) {
+		return errors.New("Execution aborted, nothing was changed")
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d attempted action(s) failed", failed, len(actions)-notAttempted)
+	}
+	if notAttempted > 0 {
+		return errors.Errorf("%d of %d action(s) not attempted", notAttempted, len(actions))
+	}
+	return nil
+}
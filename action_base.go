@@ -2,9 +2,12 @@ package cli
 
 import (
 	"log"
+	"net/http"
 	"os/exec"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/andygrunwald/go-jira"
 	"github.com/pkg/errors"
@@ -20,6 +23,13 @@ type IssueActionBase interface {
 	IsBuilt() bool
 }
 
+// Implemented by actions whose changes can be reverted.
+// Executes the action and returns the actions which would revert it,
+// based on the state the change overwrote. Returns none if nothing was changed
+type RevertibleAction interface {
+	ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error)
+}
+
 type Action interface {
 	Key() string
 	Description() string
@@ -43,6 +53,30 @@ type BaseAction struct {
 
 func (a BaseAction) IsBuilt() bool { return a.built }
 
+func expectParams(a Action, params []string, min int, max int) error {
+	if len(params) < min || len(params) > max {
+		if min == max {
+			return errors.Errorf("%s expects %d parameter(s), got %d", a.Key(), min, len(params))
+		}
+		return errors.Errorf("%s expects %d to %d parameters, got %d", a.Key(), min, max, len(params))
+	}
+	return nil
+}
+
+// Applies an "update" operation such as add or remove to a multi-value field.
+// Unlike setting the field, this doesn't lose values changed concurrently
+func updateMultiValue(client *jira.Client, issueID string, field string, op string, value interface{}) error {
+	resp, err := client.Issue.UpdateIssue(issueID, map[string]interface{}{
+		"update": map[string]interface{}{
+			field: []map[string]interface{}{
+				{op: value},
+			},
+		},
+	})
+	LogHttpResponse(resp)
+	return err
+}
+
 // Start action definitions
 
 // Add comment
@@ -54,13 +88,25 @@ type AddCommentAction struct {
 }
 
 func (a AddCommentAction) Execute(issue jira.Issue, client *jira.Client) error {
-	_, resp, err := client.Issue.AddComment(issue.ID, &jira.Comment{Body: a.Comment})
-	LogHttpResponse(resp)
+	_, err := a.ExecuteRevertible(issue, client)
 	return err
 }
 
+func (a AddCommentAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	comment, resp, err := client.Issue.AddComment(issue.ID, &jira.Comment{Body: a.Comment})
+	LogHttpResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"deleteComment", comment.ID}}, nil
+}
+
 func (a AddCommentAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
-	a.Comment = svc.menuService.Comment("Leave a comment")
+	var err error
+	a.Comment, err = svc.menuService.Prompt("Leave a comment", a.Comment)
+	if err != nil {
+		return nil, err
+	}
 	if a.Comment == "" {
 		return nil, errors.New("Comment can not be empty")
 	}
@@ -72,7 +118,16 @@ func (a AddCommentAction) Build(svc *ActionBaseService) (IssueActionBase, error)
 }
 
 func (a AddCommentAction) BuildParams(params []string) (IssueActionBase, error) {
-	panic("not impl")
+	// Allow the comment to be passed unquoted
+	comment := strings.Join(params, " ")
+	if comment == "" {
+		return nil, errors.New("Comment can not be empty")
+	}
+	return AddCommentAction{
+		a.ActionType,
+		BaseAction{true},
+		comment,
+	}, nil
 }
 
 func (a AddCommentAction) ToParams() []string { return []string{a.Comment} }
@@ -86,25 +141,18 @@ type AddLabelAction struct {
 }
 
 func (a AddLabelAction) Execute(issue jira.Issue, client *jira.Client) error {
-	for _, existing := range issue.Fields.Labels {
-		if existing == string(a.Label) {
-			log.Printf("Label already exists, nothing to do")
-			return nil
-		}
-	}
-
-	issue.Fields.Labels = append(issue.Fields.Labels, string(a.Label))
-
-	_, resp, err := client.Issue.Update(&jira.Issue{
-		Key: issue.Key,
-		Fields: &jira.IssueFields{
-			Labels: issue.Fields.Labels,
-		},
-	})
-	LogHttpResponse(resp)
+	_, err := a.ExecuteRevertible(issue, client)
 	return err
 }
 
+func (a AddLabelAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	err := updateMultiValue(client, issue.ID, "labels", "add", string(a.Label))
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"removeLabel", string(a.Label)}}, nil
+}
+
 func (a AddLabelAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
 	formatters := make([]Formatter, len(svc.config.LabelsAllowed))
 	for i, v := range svc.config.LabelsAllowed {
@@ -113,6 +161,7 @@ func (a AddLabelAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
 	idxs, cancelled, err := FzfSelect(formatters, SelectOptions{
 		Prompt: "Please select a label to add",
 		One:    true,
+		Query:  string(a.Label),
 	}, 0)
 	if err != nil {
 		return nil, err
@@ -131,11 +180,78 @@ func (a AddLabelAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
 }
 
 func (a AddLabelAction) BuildParams(params []string) (IssueActionBase, error) {
-	panic("not impl")
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return AddLabelAction{
+		a.ActionType,
+		BaseAction{true},
+		Label(params[0]),
+	}, nil
 }
 
 func (a AddLabelAction) ToParams() []string { return []string{string(a.Label)} }
 
+// Remove label
+
+type RemoveLabelAction struct {
+	ActionType
+	BaseAction
+	Label Label
+}
+
+func (a RemoveLabelAction) Execute(issue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(issue, client)
+	return err
+}
+
+func (a RemoveLabelAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	err := updateMultiValue(client, issue.ID, "labels", "remove", string(a.Label))
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"addLabel", string(a.Label)}}, nil
+}
+
+func (a RemoveLabelAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	formatters := make([]Formatter, len(svc.config.LabelsAllowed))
+	for i, v := range svc.config.LabelsAllowed {
+		formatters[i] = v
+	}
+	idxs, cancelled, err := FzfSelect(formatters, SelectOptions{
+		Prompt: "Please select a label to remove",
+		One:    true,
+		Query:  string(a.Label),
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	if cancelled {
+		return nil, CancelError()
+	}
+	if len(idxs) != 1 {
+		panic("expected exactly one")
+	}
+	return RemoveLabelAction{
+		a.ActionType,
+		BaseAction{true},
+		svc.config.LabelsAllowed[idxs[0]],
+	}, nil
+}
+
+func (a RemoveLabelAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return RemoveLabelAction{
+		a.ActionType,
+		BaseAction{true},
+		Label(params[0]),
+	}, nil
+}
+
+func (a RemoveLabelAction) ToParams() []string { return []string{string(a.Label)} }
+
 // Add label
 
 type AssignUserAction struct {
@@ -144,14 +260,85 @@ type AssignUserAction struct {
 	UserName string
 }
 
+// Prefixes an account ID given in place of a user name, since Jira Cloud has no user names
+const accountIdPrefix = "accountid:"
+
+// The user identified by a user name or a prefixed account ID
+func userOf(userName string) *jira.User {
+	if accountId := strings.TrimPrefix(userName, accountIdPrefix); accountId != userName {
+		return &jira.User{AccountID: accountId}
+	}
+	return &jira.User{Name: userName}
+}
+
+// Whether user is the one identified by a user name or a prefixed account ID
+func isUser(user *jira.User, userName string) bool {
+	other := userOf(userName)
+	if other.AccountID != "" {
+		return user.AccountID == other.AccountID
+	}
+	return user.Name != "" && user.Name == other.Name
+}
+
+// Whether the issue is assigned as assignUser would assign it, with an empty user name meaning unassigned
+func isAssignee(assignee *jira.User, userName string) bool {
+	if assignee == nil || userName == "" {
+		return assignee == nil && userName == ""
+	}
+	return isUser(assignee, userName)
+}
+
 func (a AssignUserAction) Execute(issue jira.Issue, client *jira.Client) error {
-	resp, err := client.Issue.UpdateAssignee(issue.ID, &jira.User{Name: a.UserName})
-	LogHttpResponse(resp)
+	_, err := a.ExecuteRevertible(issue, client)
 	return err
 }
 
+// An empty user name unassigns the issue. A user may be given by account ID, see accountIdPrefix
+func (a AssignUserAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	var previous *jira.User
+	if issue.Fields != nil {
+		previous = issue.Fields.Assignee
+	}
+	if isAssignee(previous, a.UserName) {
+		log.Printf("Already assigned, nothing to do")
+		return nil, nil
+	}
+
+	var (
+		resp *jira.Response
+		err  error
+	)
+	if a.UserName == "" {
+		// The user's name must be sent as null, which UpdateAssignee can't do
+		var req *http.Request
+		req, err = client.NewRequest("PUT", "rest/api/2/issue/"+issue.ID+"/assignee", map[string]interface{}{"name": nil})
+		if err != nil {
+			return nil, err
+		}
+		resp, err = client.Do(req, nil)
+	} else {
+		resp, err = client.Issue.UpdateAssignee(issue.ID, userOf(a.UserName))
+	}
+	LogHttpResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{assigneeInverse(previous)}, nil
+}
+
+// Restores the assignee, by account ID where there is one since Jira Cloud has no user names
+func assigneeInverse(previous *jira.User) ActionSpec {
+	if previous == nil {
+		return ActionSpec{"assignUser", ""}
+	}
+	if previous.AccountID != "" {
+		return ActionSpec{"assignUser", accountIdPrefix + previous.AccountID}
+	}
+	return ActionSpec{"assignUser", previous.Name}
+}
+
 func (a AssignUserAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
-	err := svc.menuService.userFavoritesMenu.Select("Select a user to assign")
+	err := svc.menuService.userFavoritesMenu.Select("Select a user to assign", a.UserName)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +352,14 @@ func (a AssignUserAction) Build(svc *ActionBaseService) (IssueActionBase, error)
 }
 
 func (a AssignUserAction) BuildParams(params []string) (IssueActionBase, error) {
-	panic("not impl")
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return AssignUserAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
 }
 
 func (a AssignUserAction) ToParams() []string { return []string{string(a.UserName)} }
@@ -181,7 +375,38 @@ type RelateOneAction struct {
 	Comment         string
 }
 
+// Whether the link between the subject and the other issue is of this action's type
+func (a RelateOneAction) isLink(link *jira.IssueLink) bool {
+	if link.Type.Name != a.IssueLinkType.Name {
+		return false
+	}
+	for _, other := range []*jira.Issue{link.InwardIssue, link.OutwardIssue} {
+		if other == nil {
+			continue
+		}
+		if (a.SubjectIssue.Key != "" && other.Key == a.SubjectIssue.Key) ||
+			(a.SubjectIssue.ID != "" && other.ID == a.SubjectIssue.ID) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a RelateOneAction) Execute(objectIssue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(objectIssue, client)
+	return err
+}
+
+func (a RelateOneAction) ExecuteRevertible(objectIssue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	if objectIssue.Fields != nil {
+		for _, link := range objectIssue.Fields.IssueLinks {
+			if a.isLink(link) {
+				log.Printf("Link already exists, nothing to do")
+				return nil, nil
+			}
+		}
+	}
+
 	var (
 		outwardIssue jira.Issue
 		inwardIssue  jira.Issue
@@ -197,12 +422,29 @@ func (a RelateOneAction) Execute(objectIssue jira.Issue, client *jira.Client) er
 		ID:           "",
 		Self:         "",
 		Type:         a.IssueLinkType,
-		OutwardIssue: &jira.Issue{Key: outwardIssue.Key},
-		InwardIssue:  &jira.Issue{Key: inwardIssue.Key},
+		OutwardIssue: &jira.Issue{ID: outwardIssue.ID, Key: outwardIssue.Key},
+		InwardIssue:  &jira.Issue{ID: inwardIssue.ID, Key: inwardIssue.Key},
 		Comment:      &jira.Comment{Body: a.Comment},
 	})
 	LogHttpResponse(resp)
-	return err
+	if err != nil {
+		return nil, err
+	}
+
+	// Jira doesn't return the created link, so find it
+	linked, resp, err := client.Issue.Get(objectIssue.ID, &jira.GetQueryOptions{Fields: "issuelinks"})
+	LogHttpResponse(resp)
+	if err != nil {
+		log.Printf("Link created, but it can't be reverted: %s", err.Error())
+		return nil, nil
+	}
+	for _, link := range linked.Fields.IssueLinks {
+		if a.isLink(link) {
+			return []ActionSpec{{"deleteLink", link.ID}}, nil
+		}
+	}
+	log.Printf("Link created, but it can't be reverted: not found on %s", objectIssue.Key)
+	return nil, nil
 }
 
 func (a RelateOneAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
@@ -211,17 +453,20 @@ func (a RelateOneAction) Build(svc *ActionBaseService) (IssueActionBase, error)
 		return nil, err
 	}
 
-	subjectIssue, err := svc.menuService.issueSearchMenu.Search("Select an issue as a starting point")
+	subjectIssue, err := svc.menuService.issueSearchMenu.Search("Select an issue as a starting point", a.SubjectIssue.Key)
 	if err != nil {
 		return nil, err
 	}
 
-	err = svc.menuService.issueLinkTypeMenu.Select(subjectIssue)
+	err = svc.menuService.issueLinkTypeMenu.Select(subjectIssue, a.IssueLinkType, a.SubjectIsInward)
 	if err != nil {
 		return nil, err
 	}
 
-	comment := svc.menuService.Comment("Leave a comment (optional)")
+	comment, err := svc.menuService.Prompt("Leave a comment (optional)", a.Comment)
+	if err != nil {
+		return nil, err
+	}
 
 	issueLinkType, subjectIsInward := svc.menuService.issueLinkTypeMenu.IssueLinkType()
 
@@ -236,7 +481,36 @@ func (a RelateOneAction) Build(svc *ActionBaseService) (IssueActionBase, error)
 }
 
 func (a RelateOneAction) BuildParams(params []string) (IssueActionBase, error) {
-	panic("not impl")
+	if err := expectParams(a, params, 3, 4); err != nil {
+		return nil, err
+	}
+
+	// The subject may be referred to by key or by ID
+	subjectIssue := jira.Issue{}
+	if IsIssueKey(params[0]) {
+		subjectIssue.Key = params[0]
+	} else {
+		subjectIssue.ID = params[0]
+	}
+
+	subjectIsInward, err := strconv.ParseBool(params[2])
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid value for subjectIsInward: %s", params[2])
+	}
+
+	comment := ""
+	if len(params) == 4 {
+		comment = params[3]
+	}
+
+	return RelateOneAction{
+		a.ActionType,
+		BaseAction{true},
+		subjectIssue,
+		jira.IssueLinkType{Name: params[1]},
+		subjectIsInward,
+		comment,
+	}, nil
 }
 
 func (a RelateOneAction) ToParams() []string {
@@ -244,9 +518,283 @@ func (a RelateOneAction) ToParams() []string {
 	if a.SubjectIsInward {
 		subjectIsInward = "true"
 	}
-	return []string{a.SubjectIssue.ID, a.IssueLinkType.Name, subjectIsInward, a.Comment}
+	subject := a.SubjectIssue.Key
+	if subject == "" {
+		subject = a.SubjectIssue.ID
+	}
+	return []string{subject, a.IssueLinkType.Name, subjectIsInward, a.Comment}
+}
+
+// Transition
+
+type TransitionAction struct {
+	ActionType
+	BaseAction
+	Status string
+}
+
+// Finds the transition which is either named status or leads to it
+func findTransition(transitions []jira.Transition, status string) (jira.Transition, error) {
+	for _, t := range transitions {
+		if strings.EqualFold(t.To.Name, status) || strings.EqualFold(t.Name, status) {
+			return t, nil
+		}
+	}
+	available := make([]string, len(transitions))
+	for i, t := range transitions {
+		available[i] = t.To.Name
+	}
+	return jira.Transition{}, errors.Errorf("No transition to %s, available: %s", status, strings.Join(available, ", "))
+}
+
+func (a TransitionAction) Execute(issue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(issue, client)
+	return err
+}
+
+// Reverting transitions back, as long as the workflow allows it
+func (a TransitionAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	previous := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		previous = issue.Fields.Status.Name
+	}
+	if strings.EqualFold(previous, a.Status) {
+		log.Printf("Issue is already %s, nothing to do", a.Status)
+		return nil, nil
+	}
+
+	transitions, resp, err := client.Issue.GetTransitions(issue.ID)
+	LogHttpResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := findTransition(transitions, a.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = client.Issue.DoTransition(issue.ID, transition.ID)
+	LogHttpResponse(resp)
+	if err != nil || previous == "" {
+		return nil, err
+	}
+	return []ActionSpec{{"transition", previous}}, nil
+}
+
+func (a TransitionAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	status, err := svc.menuService.Prompt("Status to transition to", a.Status)
+	if err != nil {
+		return nil, err
+	}
+	return a.BuildParams([]string{status})
+}
+
+func (a TransitionAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	if params[0] == "" {
+		return nil, errors.New("Status can not be empty")
+	}
+	return TransitionAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a TransitionAction) ToParams() []string { return []string{a.Status} }
+
+// Set fix version
+
+type SetFixVersionAction struct {
+	ActionType
+	BaseAction
+	Version string
+}
+
+func (a SetFixVersionAction) Execute(issue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(issue, client)
+	return err
+}
+
+func (a SetFixVersionAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	err := updateMultiValue(client, issue.ID, "fixVersions", "add", map[string]string{"name": a.Version})
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"removeFixVersion", a.Version}}, nil
+}
+
+func (a SetFixVersionAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	version, err := svc.menuService.Prompt("Fix version", a.Version)
+	if err != nil {
+		return nil, err
+	}
+	return a.BuildParams([]string{version})
 }
 
+func (a SetFixVersionAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	if params[0] == "" {
+		return nil, errors.New("Version can not be empty")
+	}
+	return SetFixVersionAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a SetFixVersionAction) ToParams() []string { return []string{a.Version} }
+
+// Add to sprint
+
+const activeSprint = "active"
+
+type AddToSprintAction struct {
+	ActionType
+	BaseAction
+	// A sprint ID, a sprint name, or "active" for the active sprint of the issue's project
+	Sprint string
+}
+
+// Finds the ID of the sprint among the open sprints of the boards of the issue's project
+func (a AddToSprintAction) resolveSprint(issue jira.Issue, client *jira.Client) (int, error) {
+	if id, err := strconv.Atoi(a.Sprint); err == nil {
+		return id, nil
+	}
+	if issue.Fields == nil || issue.Fields.Project.Key == "" {
+		return 0, errors.Errorf("Can't look up sprint %s, project of %s unknown", a.Sprint, issue.Key)
+	}
+
+	boards, resp, err := client.Board.GetAllBoards(&jira.BoardListOptions{
+		BoardType:      "scrum",
+		ProjectKeyOrID: issue.Fields.Project.Key,
+	})
+	LogHttpResponse(resp)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, board := range boards.Values {
+		sprints, resp, err := client.Board.GetAllSprintsWithOptions(board.ID, &jira.GetAllSprintsOptions{
+			State: "active,future",
+		})
+		LogHttpResponse(resp)
+		if err != nil {
+			return 0, err
+		}
+		for _, sprint := range sprints.Values {
+			if a.Sprint == activeSprint && sprint.State == activeSprint {
+				return sprint.ID, nil
+			}
+			if strings.EqualFold(sprint.Name, a.Sprint) {
+				return sprint.ID, nil
+			}
+		}
+	}
+	return 0, errors.Errorf("No open sprint %s found for project %s", a.Sprint, issue.Fields.Project.Key)
+}
+
+func (a AddToSprintAction) Execute(issue jira.Issue, client *jira.Client) error {
+	sprintID, err := a.resolveSprint(issue, client)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Sprint.MoveIssuesToSprint(sprintID, []string{issue.Key})
+	LogHttpResponse(resp)
+	return err
+}
+
+func (a AddToSprintAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	sprint := a.Sprint
+	if sprint == "" {
+		sprint = activeSprint
+	}
+	sprint, err := svc.menuService.Prompt("Sprint (name, ID or \"active\")", sprint)
+	if err != nil {
+		return nil, err
+	}
+	return a.BuildParams([]string{sprint})
+}
+
+func (a AddToSprintAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	if params[0] == "" {
+		return nil, errors.New("Sprint can not be empty")
+	}
+	return AddToSprintAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a AddToSprintAction) ToParams() []string { return []string{a.Sprint} }
+
+// Log work
+
+type LogWorkAction struct {
+	ActionType
+	BaseAction
+	// Formatted like Jira does, e.g. "1h 30m"
+	Spent   string
+	Comment string
+}
+
+func (a LogWorkAction) Execute(issue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(issue, client)
+	return err
+}
+
+func (a LogWorkAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	spent, err := ParseWorkDuration(a.Spent)
+	if err != nil {
+		return nil, err
+	}
+	record, err := addWorklog(client, issue.ID, spent, time.Now().Add(-spent), a.Comment)
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"deleteWorklog", record.ID}}, nil
+}
+
+func (a LogWorkAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	spent, err := svc.menuService.Prompt("Time spent (e.g. 1h 30m)", a.Spent)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := svc.menuService.Prompt("Work description (optional)", a.Comment)
+	if err != nil {
+		return nil, err
+	}
+	return a.BuildParams([]string{spent, comment})
+}
+
+func (a LogWorkAction) BuildParams(params []string) (IssueActionBase, error) {
+	if len(params) == 0 {
+		return nil, errors.Errorf("%s expects a duration", a.Key())
+	}
+	spent, err := ParseWorkDuration(params[0])
+	if err != nil {
+		return nil, err
+	}
+	return LogWorkAction{
+		a.ActionType,
+		BaseAction{true},
+		FormatWorkDuration(spent),
+		strings.Join(params[1:], " "),
+	}, nil
+}
+
+func (a LogWorkAction) ToParams() []string { return []string{a.Spent, a.Comment} }
+
 // Navigate action
 
 type NavigateAction struct {
@@ -354,12 +902,27 @@ var actions = []IssueActionBase{
 	AddLabelAction{
 		ActionType: ActionType{"addLabel", "Add label", "Add label '{{.Label}}' to _ISSUE"},
 	},
+	RemoveLabelAction{
+		ActionType: ActionType{"removeLabel", "Remove label", "Remove label '{{.Label}}' from _ISSUE"},
+	},
 	AssignUserAction{
 		ActionType: ActionType{"assignUser", "Assign user", "Assign [{{.UserName}}] to _ISSUE"},
 	},
 	RelateOneAction{
 		ActionType: ActionType{"relateOne", "Link issue", "Add link: {{.SubjectIssue.Key}}{{if .SubjectIsInward}} {{.IssueLinkType.Inward}} {{else}} {{.IssueLinkType.Outward}} {{end}}_ISSUE"},
 	},
+	TransitionAction{
+		ActionType: ActionType{"transition", "Transition", "Transition _ISSUE to '{{.Status}}'"},
+	},
+	SetFixVersionAction{
+		ActionType: ActionType{"setFixVersion", "Set fix version", "Set fix version '{{.Version}}' on _ISSUE"},
+	},
+	AddToSprintAction{
+		ActionType: ActionType{"addToSprint", "Add to sprint", "Add _ISSUE to sprint '{{.Sprint}}'"},
+	},
+	LogWorkAction{
+		ActionType: ActionType{"logWork", "Log work", "Log {{.Spent}} on _ISSUE{{if .Comment}}: {{.Comment}}{{end}}"},
+	},
 	NavigateAction{
 		ActionType: ActionType{"navigate", "Open in browser", "Open _ISSUE in browser"},
 	},
@@ -410,6 +973,26 @@ func (s *ActionBaseService) SelectAction(actionBases map[int]IssueActionBase) (i
 	return idxToId[idxs[0]], nil
 }
 
+// Builds an action non-interactively from its key and parameters
+func (s *ActionBaseService) BuildActionParams(key string, params []string) (IssueActionBase, error) {
+	return buildActionParams(getIssueActions(s.config), key, params)
+}
+
+// Like BuildActionParams, but also builds the actions which only exist to revert others.
+// Those are only available to the journal, since they delete what they are given
+func (s *ActionBaseService) BuildRevertActionParams(key string, params []string) (IssueActionBase, error) {
+	return buildActionParams(append(getIssueActions(s.config), revertActions...), key, params)
+}
+
+func buildActionParams(actionBases []IssueActionBase, key string, params []string) (IssueActionBase, error) {
+	for _, actionBase := range actionBases {
+		if actionBase.Key() == key {
+			return actionBase.BuildParams(params)
+		}
+	}
+	return nil, errors.Errorf("Unknown action %s", key)
+}
+
 func (s *ActionBaseService) BuildAction() (IssueActionBase, error) {
 	actions := getIssueActions(s.config)
 	actionTypeItems := make([]Formatter, len(actions))
@@ -431,6 +1014,12 @@ func (s *ActionBaseService) BuildAction() (IssueActionBase, error) {
 	}
 	actionBase := actions[idxs[0]]
 
+	return s.RebuildAction(actionBase)
+}
+
+// Builds the action interactively.
+// If it is already built, the current values are offered as defaults
+func (s *ActionBaseService) RebuildAction(actionBase IssueActionBase) (IssueActionBase, error) {
 	built, err := actionBase.Build(s)
 	if err != nil {
 		return nil, err
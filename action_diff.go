@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// A change to one field of an issue
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+func (c FieldChange) NoOp() bool { return c.Before == c.After }
+
+// Implemented by actions which can tell what they would change on an issue
+type DiffableAction interface {
+	Diff(issue jira.Issue) []FieldChange
+}
+
+// Implemented by diffable actions which can tell more by asking Jira.
+// An error means the action can't be executed as it is
+type JiraDiffableAction interface {
+	DiffJira(issue jira.Issue, client *jira.Client) ([]FieldChange, error)
+}
+
+// Whether executing the action would leave the issue as it is.
+// Unknown if the issue's fields weren't loaded
+func isNoOp(ia IssueAction) bool {
+	diffable, ok := ia.action.(DiffableAction)
+	if !ok || ia.issue.Fields == nil {
+		return false
+	}
+	changes := diffable.Diff(ia.issue)
+	for _, change := range changes {
+		if !change.NoOp() {
+			return false
+		}
+	}
+	return len(changes) > 0
+}
+
+// Prints a table of what each action would change.
+// Actions which change nothing or violate the policy are marked, since they may not be executed.
+// Without a client only what the loaded issues tell is shown
+func PrintDiff(actions []IssueAction, policy *Policy, client *jira.Client) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ISSUE\tFIELD\tBEFORE\tAFTER\t")
+	noOps := 0
+	for _, ia := range actions {
+		marks := make([]string, 0)
+		if violation := policy.Check(ia); violation != nil {
+			marks = append(marks, "("+violation.Format()+")")
+		}
+		if _, ok := ia.action.(RevertibleAction); !ok {
+			marks = append(marks, "(can't be reverted)")
+		}
+		diffable, ok := ia.action.(DiffableAction)
+		if !ok {
+			fmt.Fprintf(tw, "%s\t-\t\t%s\t%s\n", ia.issue.Key, IssueActionFormatter{ia}.Format(), strings.Join(marks, " "))
+			continue
+		}
+		if isNoOp(ia) {
+			marks = append(marks, "(no change, excluded)")
+			noOps++
+		}
+		changes := diffable.Diff(ia.issue)
+		if jiraDiffable, ok := ia.action.(JiraDiffableAction); ok && client != nil && !isNoOp(ia) {
+			jiraChanges, err := jiraDiffable.DiffJira(ia.issue, client)
+			if err != nil {
+				marks = append(marks, "("+err.Error()+")")
+			} else {
+				changes = jiraChanges
+			}
+		}
+		mark := strings.Join(marks, " ")
+		for _, change := range changes {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ia.issue.Key, change.Field, change.Before, change.After, mark)
+		}
+	}
+	tw.Flush()
+	if noOps > 0 {
+		fmt.Printf("%d of %d action(s) change nothing and are excluded\n", noOps, len(actions))
+	}
+}
+
+func labelsOf(issue jira.Issue) []string {
+	if issue.Fields == nil {
+		return nil
+	}
+	return issue.Fields.Labels
+}
+
+func fixVersionsOf(issue jira.Issue) []string {
+	versions := make([]string, 0)
+	if issue.Fields == nil {
+		return versions
+	}
+	for _, version := range issue.Fields.FixVersions {
+		versions = append(versions, version.Name)
+	}
+	return versions
+}
+
+func withValue(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(append([]string{}, values...), value)
+}
+
+func withoutValue(values []string, value string) []string {
+	without := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			without = append(without, v)
+		}
+	}
+	return without
+}
+
+func (a AddCommentAction) Diff(issue jira.Issue) []FieldChange {
+	return []FieldChange{{"comment", "", a.Comment}}
+}
+
+func (a AddLabelAction) Diff(issue jira.Issue) []FieldChange {
+	labels := labelsOf(issue)
+	return []FieldChange{{"labels", strings.Join(labels, ", "), strings.Join(withValue(labels, string(a.Label)), ", ")}}
+}
+
+func (a RemoveLabelAction) Diff(issue jira.Issue) []FieldChange {
+	labels := labelsOf(issue)
+	return []FieldChange{{"labels", strings.Join(labels, ", "), strings.Join(withoutValue(labels, string(a.Label)), ", ")}}
+}
+
+// Users are shown by display name, since Jira Cloud has no user names
+func (a AssignUserAction) Diff(issue jira.Issue) []FieldChange {
+	var assignee *jira.User
+	if issue.Fields != nil {
+		assignee = issue.Fields.Assignee
+	}
+	before := ""
+	if assignee != nil {
+		for _, label := range []string{assignee.DisplayName, assignee.Name, assignee.AccountID} {
+			if label != "" {
+				before = label
+				break
+			}
+		}
+	}
+	if isAssignee(assignee, a.UserName) {
+		return []FieldChange{{"assignee", before, before}}
+	}
+	return []FieldChange{{"assignee", before, a.UserName}}
+}
+
+func (a RelateOneAction) Diff(issue jira.Issue) []FieldChange {
+	subject := a.SubjectIssue.Key
+	if subject == "" {
+		subject = a.SubjectIssue.ID
+	}
+	link := a.IssueLinkType.Name + " " + subject
+	if issue.Fields != nil {
+		for _, existing := range issue.Fields.IssueLinks {
+			if a.isLink(existing) {
+				return []FieldChange{{"link", link, link}}
+			}
+		}
+	}
+	return []FieldChange{{"link", "", link}}
+}
+
+func (a TransitionAction) Diff(issue jira.Issue) []FieldChange {
+	before := ""
+	if issue.Fields != nil && issue.Fields.Status != nil {
+		before = issue.Fields.Status.Name
+	}
+	after := a.Status
+	if strings.EqualFold(before, a.Status) {
+		after = before
+	}
+	return []FieldChange{{"status", before, after}}
+}
+
+// Shows the transition which leads to the status
+func (a TransitionAction) DiffJira(issue jira.Issue, client *jira.Client) ([]FieldChange, error) {
+	changes := a.Diff(issue)
+	transitions, resp, err := client.Issue.GetTransitions(issue.ID)
+	LogHttpResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	transition, err := findTransition(transitions, a.Status)
+	if err != nil {
+		return nil, errors.Errorf("can't reach %s from %s", a.Status, changes[0].Before)
+	}
+	changes[0].After = fmt.Sprintf("%s (via %s)", transition.To.Name, transition.Name)
+	return changes, nil
+}
+
+func (a SetFixVersionAction) Diff(issue jira.Issue) []FieldChange {
+	versions := fixVersionsOf(issue)
+	return []FieldChange{{"fixVersions", strings.Join(versions, ", "), strings.Join(withValue(versions, a.Version), ", ")}}
+}
+
+func (a RemoveFixVersionAction) Diff(issue jira.Issue) []FieldChange {
+	versions := fixVersionsOf(issue)
+	return []FieldChange{{"fixVersions", strings.Join(versions, ", "), strings.Join(withoutValue(versions, a.Version), ", ")}}
+}
+
+func (a LogWorkAction) Diff(issue jira.Issue) []FieldChange {
+	return []FieldChange{{"worklog", "", "+" + a.Spent}}
+}
@@ -1,51 +1,226 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"time"
+	"os"
+	"os/signal"
+	"sync"
 
 	"github.com/andygrunwald/go-jira"
 )
 
+const defaultExecutorWorkers = 4
+
 type ExecutorService struct {
 	jiraClientFactory *JiraClientFactory
-	rateLimiter       chan time.Time
+	journal           *Journal
+	auditLog          *AuditLog
+	policy            *Policy
+	menuService       *MenuService
+	maxIssues         int
+	// How many issues are worked on at once
+	workers int
+
+	// Guards the journal and audit log, which workers write to
+	recordMutex sync.Mutex
 }
 
 // Executes actions indicated which ones failed by index
 func (e *ExecutorService) Execute(actions []IssueAction, dryRun bool) []error {
-	errs := make([]error, len(actions))
+	return e.execute(actions, dryRun, "")
+}
+
+// Executes actions which revert the journaled execution with the given ID
+func (e *ExecutorService) ExecuteRevert(actions []IssueAction, revertOf string) []error {
+	return e.execute(actions, false, revertOf)
+}
 
-	var client *jira.Client
-	if !dryRun {
-		var err error
-		client, err = e.jiraClientFactory.GetClient()
+// A dry run only prints what the actions would change
+func (e *ExecutorService) execute(actions []IssueAction, dryRun bool, revertOf string) []error {
+	errs := make([]error, len(actions))
+	if dryRun {
+		// The preview still shows what it can without a client
+		client, err := e.jiraClientFactory.GetClient()
 		if err != nil {
-			panic("Failed to get jira client: " + err.Error())
+			client = nil
 		}
+		PrintDiff(actions, e.policy, client)
+		return errs
 	}
 
-	for i, issueAction := range actions {
-		formatter := IssueActionFormatter{issueAction}
-		<-e.rateLimiter
-		fmt.Println("Executing " + formatter.Format())
-		if !dryRun {
-			errs[i] = issueAction.action.Execute(issueAction.issue, client)
+	// Denied actions are neither executed nor counted towards the limit
+	errs = e.policy.Enforce(actions, e.menuService)
+	allowedIdxs := make([]int, 0, len(actions))
+	allowed := make([]IssueAction, 0, len(actions))
+	for i, ia := range actions {
+		if errs[i] == nil {
+			allowedIdxs = append(allowedIdxs, i)
+			allowed = append(allowed, ia)
 		}
-		if errs[i] != nil {
-			fmt.Println("Error occurred during execution: " + errs[i].Error())
+	}
+
+	chunkEnds, err := e.checkSafety(allowed)
+	if err != nil {
+		fmt.Println("Not executing: " + err.Error())
+		for _, i := range allowedIdxs {
+			errs[i] = errNotAttempted
 		}
+		return errs
+	}
+	client, err := e.jiraClientFactory.GetClient()
+	if err != nil {
+		panic("Failed to get jira client: " + err.Error())
+	}
+	execution := e.journal.Begin(revertOf)
+	// Outside of recordMutex, which the workers would otherwise wait on
+	e.auditLog.ResolveUser()
+
+	// Ctrl-C stops the execution once the requests in flight are done, rather than the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	start := 0
+	for _, end := range chunkEnds {
+		if start > 0 && ctx.Err() == nil {
+			prompt := fmt.Sprintf("Continue with the next chunk (%d of %d action(s) done)", start, len(allowed))
+			if !e.menuService.Confirm(prompt) {
+				break
+			}
+		}
+		e.executeChunk(ctx, actions, allowedIdxs[start:end], errs, client, execution)
+		start = end
+	}
+	for _, i := range allowedIdxs[start:] {
+		errs[i] = errNotAttempted
+	}
+
+	notAttempted := 0
+	for _, i := range allowedIdxs {
+		if errs[i] == errNotAttempted {
+			notAttempted++
+		}
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("Cancelled, %d action(s) not attempted\n", notAttempted)
 	}
 
 	return errs
 }
 
+// Executes the actions at idxs, working on several issues at once.
+// The actions of each issue are executed one after another, in their order
+func (e *ExecutorService) executeChunk(ctx context.Context, actions []IssueAction, idxs []int, errs []error, client *jira.Client, execution *Execution) {
+	lanes := make([][]int, 0)
+	laneOf := make(map[string]int)
+	for _, i := range idxs {
+		id := actions[i].issue.ID
+		lane, prs := laneOf[id]
+		if !prs {
+			lane = len(lanes)
+			laneOf[id] = lane
+			lanes = append(lanes, nil)
+		}
+		lanes[lane] = append(lanes[lane], i)
+	}
+
+	laneChan := make(chan []int, len(lanes))
+	for _, lane := range lanes {
+		laneChan <- lane
+	}
+	close(laneChan)
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < e.workers && worker < len(lanes); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lane := range laneChan {
+				for _, i := range lane {
+					errs[i] = e.executeOne(ctx, actions[i], client, execution)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func (e *ExecutorService) executeOne(ctx context.Context, issueAction IssueAction, client *jira.Client, execution *Execution) error {
+	if ctx.Err() != nil {
+		return errNotAttempted
+	}
+	formatter := IssueActionFormatter{issueAction}
+	if isNoOp(issueAction) {
+		fmt.Println("Skipping " + formatter.Format() + " (no change)")
+		return nil
+	}
+
+	revertible, isRevertible := issueAction.action.(RevertibleAction)
+	if isRevertible {
+		fmt.Println("Executing " + formatter.Format())
+	} else {
+		fmt.Println("Executing " + formatter.Format() + " (can't be reverted)")
+	}
+
+	var (
+		inverse []ActionSpec
+		err     error
+	)
+	if isRevertible {
+		inverse, err = revertible.ExecuteRevertible(issueAction.issue, client)
+	} else {
+		err = issueAction.action.Execute(issueAction.issue, client)
+	}
+
+	e.recordMutex.Lock()
+	defer e.recordMutex.Unlock()
+
+	auditErr := e.auditLog.Append(issueAction, err)
+	if auditErr != nil {
+		fmt.Println("Failed to write audit log: " + auditErr.Error())
+	}
+	if err != nil {
+		fmt.Println("Error occurred during execution of " + formatter.Format() + ": " + err.Error())
+		return err
+	}
+
+	journalErr := e.journal.Record(execution, JournalEntry{
+		Key:        issueAction.issue.Key,
+		ID:         issueAction.issue.ID,
+		Action:     actionSpecOf(issueAction.action),
+		Revertible: isRevertible,
+		Inverse:    inverse,
+	})
+	if journalErr != nil {
+		fmt.Println("Failed to journal execution: " + journalErr.Error())
+	}
+	return nil
+}
+
 func NewExecutorService(
 	jiraClientFactory *JiraClientFactory,
+	journal *Journal,
+	auditLog *AuditLog,
+	policy *Policy,
+	menuService *MenuService,
+	safety *SafetyConfig,
+	executorConfig *ExecutorConfig,
 ) *ExecutorService {
-	rateLimiter := NewRateLimiter(time.Second/2, 2)
+	maxIssues := defaultMaxIssuesPerExecution
+	if safety != nil && safety.MaxIssuesPerExecution > 0 {
+		maxIssues = safety.MaxIssuesPerExecution
+	}
+	workers := defaultExecutorWorkers
+	if executorConfig != nil && executorConfig.Workers > 0 {
+		workers = executorConfig.Workers
+	}
 	return &ExecutorService{
-		jiraClientFactory,
-		rateLimiter,
+		jiraClientFactory: jiraClientFactory,
+		journal:           journal,
+		auditLog:          auditLog,
+		policy:            policy,
+		menuService:       menuService,
+		maxIssues:         maxIssues,
+		workers:           workers,
 	}
 }
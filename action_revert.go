@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// Actions which only exist to revert others.
+// They aren't offered in menus, since their parameters are IDs recorded during execution,
+// and only the journal builds them, see BuildRevertActionParams
+
+func errNotInteractive(a Action) error {
+	return errors.Errorf("%s can only be built from parameters", a.Key())
+}
+
+// Delete comment
+
+type DeleteCommentAction struct {
+	ActionType
+	BaseAction
+	CommentID string
+}
+
+func (a DeleteCommentAction) Execute(issue jira.Issue, client *jira.Client) error {
+	return client.Issue.DeleteComment(issue.ID, a.CommentID)
+}
+
+func (a DeleteCommentAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	return nil, errNotInteractive(a)
+}
+
+func (a DeleteCommentAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return DeleteCommentAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a DeleteCommentAction) ToParams() []string { return []string{a.CommentID} }
+
+// Delete link
+
+type DeleteLinkAction struct {
+	ActionType
+	BaseAction
+	LinkID string
+}
+
+func (a DeleteLinkAction) Execute(issue jira.Issue, client *jira.Client) error {
+	resp, err := client.Issue.DeleteLink(a.LinkID)
+	LogHttpResponse(resp)
+	return err
+}
+
+func (a DeleteLinkAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	return nil, errNotInteractive(a)
+}
+
+func (a DeleteLinkAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return DeleteLinkAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a DeleteLinkAction) ToParams() []string { return []string{a.LinkID} }
+
+// Delete worklog
+
+type DeleteWorklogAction struct {
+	ActionType
+	BaseAction
+	WorklogID string
+}
+
+func (a DeleteWorklogAction) Execute(issue jira.Issue, client *jira.Client) error {
+	req, err := client.NewRequest("DELETE", "rest/api/2/issue/"+issue.ID+"/worklog/"+a.WorklogID, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req, nil)
+	LogHttpResponse(resp)
+	return err
+}
+
+func (a DeleteWorklogAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	return nil, errNotInteractive(a)
+}
+
+func (a DeleteWorklogAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return DeleteWorklogAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a DeleteWorklogAction) ToParams() []string { return []string{a.WorklogID} }
+
+// Remove fix version
+
+type RemoveFixVersionAction struct {
+	ActionType
+	BaseAction
+	Version string
+}
+
+func (a RemoveFixVersionAction) Execute(issue jira.Issue, client *jira.Client) error {
+	_, err := a.ExecuteRevertible(issue, client)
+	return err
+}
+
+func (a RemoveFixVersionAction) ExecuteRevertible(issue jira.Issue, client *jira.Client) ([]ActionSpec, error) {
+	err := updateMultiValue(client, issue.ID, "fixVersions", "remove", map[string]string{"name": a.Version})
+	if err != nil {
+		return nil, err
+	}
+	return []ActionSpec{{"setFixVersion", a.Version}}, nil
+}
+
+func (a RemoveFixVersionAction) Build(svc *ActionBaseService) (IssueActionBase, error) {
+	return nil, errNotInteractive(a)
+}
+
+func (a RemoveFixVersionAction) BuildParams(params []string) (IssueActionBase, error) {
+	if err := expectParams(a, params, 1, 1); err != nil {
+		return nil, err
+	}
+	return RemoveFixVersionAction{
+		a.ActionType,
+		BaseAction{true},
+		params[0],
+	}, nil
+}
+
+func (a RemoveFixVersionAction) ToParams() []string { return []string{a.Version} }
+
+var revertActions = []IssueActionBase{
+	DeleteCommentAction{
+		ActionType: ActionType{"deleteComment", "Delete comment", "Delete comment {{.CommentID}} from _ISSUE"},
+	},
+	DeleteLinkAction{
+		ActionType: ActionType{"deleteLink", "Delete link", "Delete link {{.LinkID}} of _ISSUE"},
+	},
+	DeleteWorklogAction{
+		ActionType: ActionType{"deleteWorklog", "Delete worklog", "Delete worklog {{.WorklogID}} from _ISSUE"},
+	},
+	RemoveFixVersionAction{
+		ActionType: ActionType{"removeFixVersion", "Remove fix version", "Remove fix version '{{.Version}}' from _ISSUE"},
+	},
+}
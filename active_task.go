@@ -0,0 +1,302 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+const activeTaskState = "active.json"
+
+var defaultActiveTaskConfig = &ActiveTaskConfig{
+	Start: []ActionSpec{
+		{"transition", "In Progress"},
+		{"assignUser", "{{ .Me }}"},
+		{"setFixVersion", "{{ .FixVersion }}"},
+		{"addToSprint", "active"},
+	},
+	Review: []ActionSpec{
+		{"transition", "In Review"},
+		{"addComment", "{{ .Comment }}"},
+	},
+	Complete: []ActionSpec{
+		{"transition", "Done"},
+	},
+}
+
+// The ticket currently being worked on
+type ActiveTask struct {
+	Key     string    `json:"key"`
+	Started time.Time `json:"started"`
+}
+
+// The data available to the parameter templates of activetask actions
+type ActiveTaskParams struct {
+	Issue   jira.Issue
+	Me      string
+	Comment string
+	// The first version of the issue's project which is neither released nor archived
+	FixVersion string
+}
+
+// Runs the configured action sequences which make up the activetask workflow:
+// start, review and complete
+type ActiveTaskService struct {
+	config            *ActiveTaskConfig
+	jiraClientFactory *JiraClientFactory
+	issueFetcher      *IssueFetcher
+	actionBaseService *ActionBaseService
+	executorService   *ExecutorService
+	menuService       *MenuService
+}
+
+// Returns nil if there is no active task
+func (s *ActiveTaskService) Active() (*ActiveTask, error) {
+	active := &ActiveTask{}
+	found, err := loadState(activeTaskState, active)
+	if err != nil || !found {
+		return nil, err
+	}
+	return active, nil
+}
+
+func (s *ActiveTaskService) Clear() error {
+	return removeState(activeTaskState)
+}
+
+// Resolves an empty key to the active task
+func (s *ActiveTaskService) resolveKey(key string) (string, error) {
+	if key != "" {
+		return key, nil
+	}
+	active, err := s.Active()
+	if err != nil {
+		return "", err
+	}
+	if active == nil {
+		return "", errors.New("No active task, please provide an issue key")
+	}
+	return active.Key, nil
+}
+
+func (s *ActiveTaskService) Start(key string, comment string, assumeYes bool) error {
+	key, err := s.run(s.config.Start, key, comment, assumeYes)
+	if err != nil {
+		return err
+	}
+	return saveState(activeTaskState, &ActiveTask{Key: key, Started: time.Now()})
+}
+
+func (s *ActiveTaskService) Review(key string, comment string, assumeYes bool) error {
+	key, err := s.resolveKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.run(s.config.Review, key, comment, assumeYes)
+	return err
+}
+
+// Completes the task, which is no longer active afterwards
+func (s *ActiveTaskService) Complete(key string, comment string, assumeYes bool) error {
+	key, err := s.resolveKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.run(s.config.Complete, key, comment, assumeYes)
+	if err != nil {
+		return err
+	}
+
+	active, err := s.Active()
+	if err != nil {
+		return err
+	}
+	if active != nil && active.Key == key {
+		return s.Clear()
+	}
+	return nil
+}
+
+func specsReference(specs []ActionSpec, field string) bool {
+	for _, spec := range specs {
+		for _, param := range spec {
+			if strings.Contains(param, "."+field) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func renderParam(param string, params ActiveTaskParams) (string, error) {
+	tpl, err := template.New("").Option("missingkey=error").Parse(param)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to parse parameter template %s", param)
+	}
+	out := new(strings.Builder)
+	err = tpl.Execute(out, params)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to execute parameter template %s", param)
+	}
+	return out.String(), nil
+}
+
+// Jira lists the versions of a project in their planned order
+func (s *ActiveTaskService) nextFixVersion(issue jira.Issue) (string, error) {
+	if issue.Fields == nil || issue.Fields.Project.Key == "" {
+		return "", errors.Errorf("Project of %s unknown", issue.Key)
+	}
+	client, err := s.jiraClientFactory.GetClient()
+	if err != nil {
+		return "", err
+	}
+	project, resp, err := client.Project.Get(issue.Fields.Project.Key)
+	LogHttpResponse(resp)
+	if err != nil {
+		return "", errors.Wrapf(err, "Failed to get versions of project %s", issue.Fields.Project.Key)
+	}
+	for _, version := range project.Versions {
+		released := version.Released != nil && *version.Released
+		archived := version.Archived != nil && *version.Archived
+		if !released && !archived {
+			return version.Name, nil
+		}
+	}
+	return "", errors.Errorf("Project %s has no unreleased version", issue.Fields.Project.Key)
+}
+
+// The current user as assignUser expects it, by account ID where there is one.
+// An empty user would unassign the issue instead
+func (s *ActiveTaskService) me() (string, error) {
+	user, err := s.jiraClientFactory.CurrentJiraUser()
+	if err != nil {
+		return "", err
+	}
+	if user.AccountID != "" {
+		return accountIdPrefix + user.AccountID, nil
+	}
+	if user.Name == "" {
+		return "", errors.New("Jira didn't identify the current user, please set client.username")
+	}
+	return user.Name, nil
+}
+
+// Builds the actions described by specs for the issue and executes them in order.
+// Returns the current key of the issue, which differs if it was moved
+func (s *ActiveTaskService) run(specs []ActionSpec, key string, comment string, assumeYes bool) (string, error) {
+	if len(specs) == 0 {
+		return "", errors.New("No actions configured")
+	}
+
+	issues, missing, err := s.issueFetcher.FetchByKeys([]string{key})
+	if err != nil {
+		return "", err
+	}
+	if len(missing) > 0 || len(issues) != 1 {
+		return "", errors.Errorf("Issue does not exist: %s", key)
+	}
+
+	params := ActiveTaskParams{Issue: issues[0], Comment: comment}
+	if specsReference(specs, "Me") {
+		params.Me, err = s.me()
+		if err != nil {
+			return "", err
+		}
+	}
+	if specsReference(specs, "FixVersion") {
+		params.FixVersion, err = s.nextFixVersion(issues[0])
+		if err != nil {
+			return "", err
+		}
+	}
+	if params.Comment == "" && specsReference(specs, "Comment") {
+		params.Comment = s.menuService.Comment("Leave a comment")
+	}
+
+	actions := make([]IssueAction, 0, len(specs))
+	for _, spec := range specs {
+		if len(spec) == 0 {
+			return "", errors.New("Empty action in activetask config")
+		}
+		rendered := make([]string, len(spec)-1)
+		for i, param := range spec[1:] {
+			rendered[i], err = renderParam(param, params)
+			if err != nil {
+				return "", err
+			}
+		}
+		actionBase, err := s.actionBaseService.BuildActionParams(spec[0], rendered)
+		if err != nil {
+			return "", err
+		}
+		actions = append(actions, IssueAction{issues[0], actionBase})
+	}
+
+	return issues[0].Key, confirmAndExecute(s.executorService, s.menuService, actions, assumeYes)
+}
+
+func NewActiveTaskService(
+	config *ActiveTaskConfig,
+	jiraClientFactory *JiraClientFactory,
+	issueFetcher *IssueFetcher,
+	actionBaseService *ActionBaseService,
+	executorService *ExecutorService,
+	menuService *MenuService,
+) *ActiveTaskService {
+	// Fall back to the defaults for any command that isn't configured
+	merged := *defaultActiveTaskConfig
+	if config != nil {
+		if len(config.Start) > 0 {
+			merged.Start = config.Start
+		}
+		if len(config.Review) > 0 {
+			merged.Review = config.Review
+		}
+		if len(config.Complete) > 0 {
+			merged.Complete = config.Complete
+		}
+	}
+	return &ActiveTaskService{
+		&merged,
+		jiraClientFactory,
+		issueFetcher,
+		actionBaseService,
+		executorService,
+		menuService,
+	}
+}
+
+// Runs an activetask command. The key defaults to the active task where it makes sense
+func RunActive(app *App, command string, key string, comment string, assumeYes bool) error {
+	svc := app.activeTaskService
+	switch command {
+	case "", "status":
+		active, err := svc.Active()
+		if err != nil {
+			return err
+		}
+		if active == nil {
+			fmt.Println("No active task")
+			return nil
+		}
+		fmt.Printf("%s (since %s)\n", active.Key, active.Started.Format(time.RFC1123))
+		return nil
+	case "start":
+		if key == "" {
+			return errors.New("Please provide an issue key to start")
+		}
+		return svc.Start(key, comment, assumeYes)
+	case "review":
+		return svc.Review(key, comment, assumeYes)
+	case "complete":
+		return svc.Complete(key, comment, assumeYes)
+	case "clear":
+		return svc.Clear()
+	default:
+		return errors.Errorf("Unknown active command %s", command)
+	}
+}
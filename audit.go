@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	auditFile = "audit.jsonl"
+	// Recorded when the current user can't be identified
+	unknownAuditUser = "unknown"
+)
+
+// One executed action, as recorded in the audit log
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	URL  string    `json:"url"`
+	User string    `json:"user"`
+	Key  string    `json:"key"`
+	// The canonical action, e.g. "addLabel released"
+	Action string `json:"action"`
+	Failed bool   `json:"failed"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (r AuditRecord) Format() string {
+	result := "ok"
+	if r.Failed {
+		result = "FAILED: " + r.Error
+	}
+	return fmt.Sprintf("%s %s %s %s %s: %s",
+		r.Time.Format("2006-01-02 15:04:05"), r.URL, r.User, r.Key, r.Action, result)
+}
+
+// Selects audit records. Zero values match everything
+type AuditFilter struct {
+	Key    string
+	Action string
+	Since  time.Time
+	Until  time.Time
+	Failed bool
+}
+
+func (f AuditFilter) Matches(r AuditRecord) bool {
+	if f.Key != "" && r.Key != f.Key {
+		return false
+	}
+	if f.Action != "" && strings.SplitN(r.Action, " ", 2)[0] != f.Action {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.Time.Before(f.Until) {
+		return false
+	}
+	return !f.Failed || r.Failed
+}
+
+// Appends every executed action to $XDG_STATE_HOME/gojira-cli/audit.jsonl
+type AuditLog struct {
+	jiraClientFactory *JiraClientFactory
+	url               string
+	// Who the records are attributed to, see ResolveUser
+	user string
+}
+
+// Looks up the current user once, so appending records doesn't need a request
+func (l *AuditLog) ResolveUser() {
+	if l.user != "" && l.user != unknownAuditUser {
+		return
+	}
+	user, err := l.jiraClientFactory.CurrentUser()
+	if err != nil {
+		fmt.Println("Failed to identify the current user for the audit log: " + err.Error())
+		user = unknownAuditUser
+	}
+	l.user = user
+}
+
+func (l *AuditLog) Append(ia IssueAction, err error) error {
+	if l.user == "" {
+		l.ResolveUser()
+	}
+	record := AuditRecord{
+		Time:   time.Now(),
+		URL:    l.url,
+		User:   l.user,
+		Key:    ia.issue.Key,
+		Action: canonicalAction(ia.action),
+		Failed: err != nil,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	bs, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	filePath, err := stateFile(auditFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(bs, '\n'))
+	return err
+}
+
+// Returns the matching records, oldest first
+func (l *AuditLog) Read(filter AuditFilter) ([]AuditRecord, error) {
+	filePath, err := stateFile(auditFile)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return []AuditRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := make([]AuditRecord, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := AuditRecord{}
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to parse line %d of %s", line, filePath)
+		}
+		if filter.Matches(record) {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func NewAuditLog(jiraClientFactory *JiraClientFactory, url string) *AuditLog {
+	return &AuditLog{jiraClientFactory: jiraClientFactory, url: url}
+}
+
+func RunHistory(app *App, filter AuditFilter) error {
+	records, err := app.auditLog.Read(filter)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		fmt.Println(record.Format())
+	}
+	return nil
+}
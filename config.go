@@ -20,33 +20,125 @@ queries:
   "all":
 actions:
   "helloworld": echo {{ .Issue.ID }}
+active:
+  start:
+    - [transition, In Progress]
+    - [assignUser, "{{ .Me }}"]
+    - [setFixVersion, "{{ .FixVersion }}"]
+    - [addToSprint, active]
+  review:
+    - [transition, In Review]
+    - [addComment, "{{ .Comment }}"]
+  complete:
+    - [transition, Done]
+timer:
+  granularity: 15m
+worklogTargets:
+  "review": ""
+  "meetings": ""
+safety:
+  maxIssuesPerExecution: 10
+executor:
+  workers: 4
+policy:
+  - effect: deny
+    actions: [addComment]
+    statuses: [Closed]
+    reason: Closed issues don't get comments
+  # - effect: deny
+  #   projects: [SEC]
+  #   reason: Security issues are handled manually
+  # - effect: confirm
+  #   actions: [assignUser]
+  #   allowedUsers: ["@favorites"]
+  #   reason: Assigning someone outside favorites
+menu:
+  startAdvanced: false
+  # Leave out items to hide them
+  # simple: [quit, search, chooseAction, undo, redo, view, advanced, doIt]
+  hotkeys:
+    search: s
 client:
   url: ""
   keyfile: ""
   certfile: ""
   username: ""
   passfile: ""
+  rateLimit:
+    requestsPerSecond: 2
+    burst: 2
 `
 
 type FavoritesConfig struct {
 	Users []string `yaml:"users"`
 }
 
+// An action identified by its key followed by its parameters, e.g. [addLabel, released]
+type ActionSpec []string
+
+// The sequences of actions run by each activetask command.
+// Parameters are templates with access to .Issue, .Me and .Comment
+type ActiveTaskConfig struct {
+	Start    []ActionSpec `yaml:"start"`
+	Review   []ActionSpec `yaml:"review"`
+	Complete []ActionSpec `yaml:"complete"`
+}
+
+type TimerConfig struct {
+	// Logged time is rounded to a multiple of this, e.g. "15m"
+	Granularity string `yaml:"granularity"`
+}
+
+// Which items the workbench menu shows, and in which order, by their keys
+type MenuConfig struct {
+	StartAdvanced bool     `yaml:"startAdvanced"`
+	Simple        []string `yaml:"simple"`
+	Advanced      []string `yaml:"advanced"`
+	// Overrides the default hotkeys, e.g. search: "/"
+	Hotkeys map[string]string `yaml:"hotkeys"`
+}
+
+type SafetyConfig struct {
+	// Executions touching more issues need an explicit override, or are run in chunks
+	MaxIssuesPerExecution int `yaml:"maxIssuesPerExecution"`
+}
+
+type ExecutorConfig struct {
+	// How many issues are worked on at once. Requests still share one rate limit
+	Workers int `yaml:"workers"`
+}
+
 type Config struct {
 	// WOuld prefer to use the saved JQL queries for the user, but I
 	JQLs    map[string]string `yaml:"queries"`
 	Actions map[string]string `yaml:"actions"`
 	// Jira doesn't seem to keep a list of existing labels so I gotta add them via config
-	LabelsAllowed []Label          `yaml:"labels"`
-	Client        JiraClientConfig `yaml:"client"`
-	Favorites     *FavoritesConfig `yaml:"favorites"`
+	LabelsAllowed []Label           `yaml:"labels"`
+	Client        JiraClientConfig  `yaml:"client"`
+	Favorites     *FavoritesConfig  `yaml:"favorites"`
+	Active        *ActiveTaskConfig `yaml:"active"`
+	Timer         *TimerConfig      `yaml:"timer"`
+	// Named issues that work is logged against regularly, e.g. review: ACME-100
+	WorklogTargets map[string]string `yaml:"worklogTargets"`
+	Menu           *MenuConfig       `yaml:"menu"`
+	Safety         *SafetyConfig     `yaml:"safety"`
+	Policy         []PolicyRule      `yaml:"policy"`
+	Executor       *ExecutorConfig   `yaml:"executor"`
 }
 
 type JiraClientConfig struct {
-	Url       string `yaml:"url"`
-	TokenFile string `yaml:"tokenFile"`
-	Username  string `yaml:"username"`
-	Passfile  string `yaml:"passfile"`
+	Url       string           `yaml:"url"`
+	TokenFile string           `yaml:"tokenFile"`
+	Username  string           `yaml:"username"`
+	Passfile  string           `yaml:"passfile"`
+	RateLimit *RateLimitConfig `yaml:"rateLimit"`
+}
+
+// How fast requests are sent to this Jira instance.
+// The rate is lowered further when Jira reports it is running out of budget
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	Burst             int     `yaml:"burst"`
 }
 
 type ConfigLoader interface {
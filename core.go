@@ -17,6 +17,7 @@ type App struct {
 	formatterConfig *FormatterConfig
 
 	jiraClientFactory  *JiraClientFactory
+	issueFetcher       *IssueFetcher
 	favoritesService   *FavoritesService
 	menuService        *MenuService
 	issueFormatter     IssueFormatter
@@ -25,6 +26,14 @@ type App struct {
 	executorService    *ExecutorService
 	actionBaseService  *ActionBaseService
 	workbenchService   WorkbenchService
+	activeTaskService  *ActiveTaskService
+	worklogService     *WorklogService
+	timerService       *TimerService
+	sessionService     *SessionService
+	journal            *Journal
+	auditLog           *AuditLog
+	policy             *Policy
+	revertService      *RevertService
 }
 
 func NewApp() *App {
@@ -38,6 +47,7 @@ func NewApp() *App {
 	}
 
 	app.jiraClientFactory = NewJiraClientFactory(app)
+	app.issueFetcher = NewIssueFetcher(app.jiraClientFactory)
 
 	// Create stateful entities
 	app.workbench = InitWorkbench()
@@ -50,26 +60,19 @@ func NewApp() *App {
 	app.issueFormatter = NewIssueFormatter(app.formatterConfig)
 	app.issueSelector = &IssueSelector{app.issueFormatter}
 	app.issueSearchService = NewIssueSearchService(app.issueSearcher, app.menuService, app.issueSelector)
-	app.executorService = NewExecutorService(app.jiraClientFactory)
+	app.journal = NewJournal()
+	app.auditLog = NewAuditLog(app.jiraClientFactory, app.config.Client.Url)
+	app.policy = NewPolicy(app.config.Policy, app.favoritesService)
+	app.executorService = NewExecutorService(app.jiraClientFactory, app.journal, app.auditLog, app.policy, app.menuService, app.config.Safety, app.config.Executor)
 	app.actionBaseService = NewActionBaseService(app.config, app.menuService, app.issueSearchService)
-	app.workbenchService = NewWorkbenchService(app.issueSelector, app.issueSearchService, app.actionBaseService, app.executorService)
-
-	mainMenuActions = MainMenuActions(app, app.workbenchService, app.menuService, app.workbench)
-
-	mainMenuActionLabels := make([]string, len(mainMenuActions))
-	for i, action := range mainMenuActions {
-		mainMenuActionLabels[i] = action.label
-	}
-
-	advancedMenuActions = AdvancedMenuActions(app, app.workbenchService, app.menuService, app.workbench)
-
-	advancedMenuActionLabels := make([]string, len(advancedMenuActions))
-	for i, action := range advancedMenuActions {
-		advancedMenuActionLabels[i] = action.label
-	}
-
-	app.menuService.RegisterMainMenu(mainMenuActionLabels)
-	app.menuService.RegisterAdvancedMenu(advancedMenuActionLabels)
+	app.workbenchService = NewWorkbenchService(app.issueSelector, app.issueSearchService, app.actionBaseService, app.executorService, app.issueFetcher)
+	app.worklogService = NewWorklogService(app.jiraClientFactory, app.config.WorklogTargets)
+	app.timerService = NewTimerService(app.config.Timer, app.worklogService, app.menuService)
+	app.revertService = NewRevertService(app.journal, app.issueFetcher, app.actionBaseService, app.executorService, app.menuService)
+	app.sessionService = NewSessionService(app.issueFetcher, app.actionBaseService, app.menuService)
+	app.activeTaskService = NewActiveTaskService(app.config.Active, app.jiraClientFactory, app.issueFetcher, app.actionBaseService, app.executorService, app.menuService)
+
+	app.menuService.RegisterWorkbenchMenu(WorkbenchMenuActions(app, app.workbenchService, app.menuService, app.workbench))
 	app.menuService.RegisterFormatterMenu(&FormatterMenu{app.formatterConfig, 0})
 	app.menuService.RegisterIssueSearchMenu(app)
 	app.menuService.RegisterIssueLinkTypeMenu(app)
@@ -78,6 +81,11 @@ func NewApp() *App {
 	return app
 }
 
+// Releases what the app holds on to, such as the rate limiter
+func (app *App) Close() {
+	app.jiraClientFactory.Close()
+}
+
 var (
 	config *Config
 
@@ -93,122 +101,228 @@ var (
 	executorService    *ExecutorService
 	actionBaseService  *ActionBaseService
 	workbenchService   WorkbenchService
-
-	mainMenuActions     []*MenuAction
-	advancedMenuActions []*MenuAction
 )
 
 type MenuAction struct {
 	action func() error
 	label  string
+	// Identifies the action in the menu config
+	key string
+	// Selects the action with a single key press
+	hotkey string
 }
 
-func MainMenuActions(app *App, svc WorkbenchService, menuService *MenuService, w *Workbench) []*MenuAction {
+// Default order of the workbench menu in each mode
+var (
+	defaultSimpleMenu = []string{
+		"quit", "search", "chooseAction", "undo", "redo", "view", "advanced", "doIt",
+	}
+	defaultAdvancedMenu = []string{
+		"quit", "simple", "reset", "undo", "redo", "view",
+		"search", "queryOperation", "queue", "select", "remove",
+		"chooseAction", "addAction", "removeAction", "editAction",
+		"issueFormat", "issueOrder",
+		"execute", "retryFailed", "dropFailed", "preview", "validate", "exportPlan", "revert",
+	}
+)
+
+// All the actions the workbench menu can show
+func WorkbenchMenuActions(app *App, svc WorkbenchService, menuService *MenuService, w *Workbench) []*MenuAction {
 	return []*MenuAction{
 		&MenuAction{
-			action: func() error { os.Exit(0); return nil },
+			action: func() error { app.Close(); os.Exit(0); return nil },
 			label:  "Quit",
+			key:    "quit",
+			hotkey: "q",
 		},
 		&MenuAction{
-			action: func() error { return svc.AddIssuesInteractive(w) },
-			label:  "Seach",
+			action: func() error { menuService.SetAdvancedMenu(true); return nil },
+			label:  "Advanced menu",
+			key:    "advanced",
+			hotkey: "m",
 		},
 		&MenuAction{
-			action: func() error {
-				return svc.SelectActionInteractive(w)
-			},
-			label: "Set action",
+			action: func() error { menuService.SetAdvancedMenu(false); return nil },
+			label:  "Simple menu",
+			key:    "simple",
+			hotkey: "m",
 		},
 		&MenuAction{
-			action: func() error {
-				if w.actionId == 0 {
-					log.Panicln("No action selected")
-				}
-				w.SelectAll()
-				w.AssignSelected()
-				err := svc.Execute(w, false)
-				w.ClearSelection()
-				return err
-			},
-			label: "Do it",
+			action: func() error { w.Reset(); return nil },
+			label:  "Reset all",
+			key:    "reset",
+			hotkey: "X",
 		},
-	}
-}
-
-func AdvancedMenuActions(app *App, svc WorkbenchService, menuService *MenuService, w *Workbench) []*MenuAction {
-	return []*MenuAction{
 		&MenuAction{
-			action: func() error { os.Exit(0); return nil },
-			label:  "Quit",
+			action: func() error { return w.Undo() },
+			label:  "Undo",
+			key:    "undo",
+			hotkey: "u",
 		},
 		&MenuAction{
-			action: func() error { w.Reset(); return nil },
-			label:  "Reset all",
+			action: func() error { return w.Redo() },
+			label:  "Redo",
+			key:    "redo",
+			hotkey: "r",
 		},
 		&MenuAction{
-			action: func() error { return nil },
-			label:  "Print",
+			action: func() error { return pageOutput(w.Detail(app.issueFormatter, app.formatterConfig.groupBy)) },
+			label:  "View workbench",
+			key:    "view",
+			hotkey: "v",
 		},
-		&MenuAction{ // TODO hide unless in debug mode
+		&MenuAction{
 			action: func() error {
-				menuService.userFavoritesMenu.Select("Pick a user")
+				menuService.userFavoritesMenu.Select("Pick a user", "")
 				log.Println("selected : " + menuService.userFavoritesMenu.SelectedUser())
 				return nil
 			},
 			label: "Debug",
+			key:   "debug",
 		},
 		&MenuAction{
 			action: func() error { return svc.AddIssuesInteractive(w) },
-			label:  "Seach / Add issues to workbench",
+			label:  "Search / Add issues to workbench",
+			key:    "search",
+			hotkey: "s",
+		},
+		&MenuAction{
+			action: func() error { return svc.QueryOperationInteractive(w) },
+			label:  "Add / Keep / Remove issues by query",
+			key:    "queryOperation",
+			hotkey: "f",
 		},
 		&MenuAction{
 			action: func() error { return svc.AssignInteractive(w) },
 			label:  "Queue issues",
+			key:    "queue",
+			hotkey: "e",
 		},
 		&MenuAction{
 			action: func() error { return svc.SelectInteractive(w) },
 			label:  "Select issues",
+			key:    "select",
+			hotkey: "l",
 		},
 		&MenuAction{
 			action: func() error { return svc.FilterInteractive(w) },
 			label:  "Remove issues",
+			key:    "remove",
+			hotkey: "x",
 		},
 		&MenuAction{
 			action: func() error { return svc.SelectActionInteractive(w) },
 			label:  "Choose action to be assigned",
+			key:    "chooseAction",
+			hotkey: "a",
 		},
 		&MenuAction{
 			action: func() error { return svc.AddActionInteractive(w) },
 			label:  "Add action",
+			key:    "addAction",
+			hotkey: "n",
 		},
 		&MenuAction{
 			action: func() error { return svc.RemoveActionInteractive(w) },
 			label:  "Remove action",
+			key:    "removeAction",
+			hotkey: "d",
 		},
 		&MenuAction{
 			action: func() error { return svc.EditActionInteractive(w) },
 			label:  "Edit action (re-add action, keeping assigned issues)",
+			key:    "editAction",
+			hotkey: "c",
 		},
 		&MenuAction{
 			action: func() error { return menuService.SelectIssueFormat() },
 			label:  "Change issue format",
+			key:    "issueFormat",
+			hotkey: "i",
+		},
+		&MenuAction{
+			action: func() error { return menuService.SelectIssueOrder() },
+			label:  "Sort / group issues",
+			key:    "issueOrder",
+			hotkey: "o",
+		},
+		&MenuAction{
+			action: func() error {
+				if w.actionId == 0 {
+					log.Panicln("No action selected")
+				}
+				w.SelectAll()
+				w.AssignSelected()
+				err := svc.Execute(w, false)
+				w.ClearSelection()
+				return err
+			},
+			label:  "Do it",
+			key:    "doIt",
+			hotkey: "g",
 		},
 		&MenuAction{
 			action: func() error { return svc.Execute(w, false) },
 			label:  "Execute",
+			key:    "execute",
+			hotkey: "E",
+		},
+		&MenuAction{
+			action: func() error { return svc.RetryFailed(w) },
+			label:  "Retry failed only",
+			key:    "retryFailed",
+			hotkey: "t",
+		},
+		&MenuAction{
+			action: func() error { w.DropFailed(); return nil },
+			label:  "Drop failed",
+			key:    "dropFailed",
+			hotkey: "T",
 		},
 		&MenuAction{
 			action: func() error { return svc.Execute(w, true) },
 			label:  "Preview",
+			key:    "preview",
+			hotkey: "p",
+		},
+		&MenuAction{
+			action: func() error { return svc.Validate(w) },
+			label:  "Validate against Jira",
+			key:    "validate",
+			hotkey: "V",
+		},
+		&MenuAction{
+			action: func() error { return svc.ExportPlanInteractive(w) },
+			label:  "Export plan",
+			key:    "exportPlan",
+			hotkey: "w",
+		},
+		&MenuAction{
+			action: func() error { return app.revertService.RevertLast(false) },
+			label:  "Revert last execution",
+			key:    "revert",
+			hotkey: "U",
 		},
 	}
 }
 
-func RunWorkbench() {
+// Runs the interactive workbench, saving it to the named session.
+// If no session is named the user picks one
+func RunWorkbench(session string) {
 	SetupRpc()
 
 	app := NewApp()
 
+	var err error
+	if session != "" {
+		err = app.sessionService.Open(session, app.workbench)
+	} else {
+		err = app.sessionService.SelectInteractive(app.workbench)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	config = app.config
 
 	workbench = app.workbench
@@ -235,17 +349,23 @@ func RunWorkbench() {
 
 		fmt.Println(workbench.Format())
 
-		cursor, err := menuService.SelectMain()
+		menuAction, err := menuService.SelectWorkbenchAction()
 		if err != nil {
 			fmt.Println(err)
 			continue
 		}
 
-		err = mainMenuActions[cursor].action()
+		// Everything a menu action changes is undone in one step
+		err = workbench.Batch(menuAction.action)
 		if err != nil && !IsCancelError(err) {
 			fmt.Println("ERROR: " + err.Error())
 		}
 
+		err = app.sessionService.Save(workbench)
+		if err != nil {
+			fmt.Println("ERROR: Failed to save session: " + err.Error())
+		}
+
 	}
 
 }
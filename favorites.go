@@ -40,13 +40,14 @@ func (m *UsersFavoritesMenu) SelectedUser() string {
 	return m.selectedUser
 }
 
-func (m *UsersFavoritesMenu) Select(prompt string) error {
+// Select a user, with the query pre-filled if given
+func (m *UsersFavoritesMenu) Select(prompt string, query string) error {
 	users := m.favorites.Users()
 	formatters := make([]Formatter, len(users))
 	for i, u := range users {
 		formatters[i] = StringFormatter(u)
 	}
-	idxs, cancelled, err := FzfSelect(formatters, SelectOptions{Prompt: prompt, One: true}, 0)
+	idxs, cancelled, err := FzfSelect(formatters, SelectOptions{Prompt: prompt, One: true, Query: query}, 0)
 	if cancelled {
 		return CancelError()
 	}
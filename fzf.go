@@ -20,7 +20,8 @@ import (
 type SelectOptions struct {
 	Prompt  string
 	One     bool
-	Exclude int // exclude this many fields from the left generated by the formatter
+	Exclude int    // exclude this many fields from the left generated by the formatter
+	Query   string // initial query, e.g. to pre-fill the current value
 }
 
 func formatterSliceToChan(formatters []Formatter) chan Formatter {
@@ -44,6 +45,9 @@ func appendFzfArgs(args []string, opts SelectOptions, rpcPort int) []string {
 	if opts.Prompt != "" {
 		args = append(args, "--prompt="+opts.Prompt+"> ")
 	}
+	if opts.Query != "" {
+		args = append(args, "--query="+opts.Query)
+	}
 
 	if rpcPort != 0 { // RPC supported
 		args = append(args, "--preview", "gojira-cli _rpc print {} | fold -w 80")
@@ -2,16 +2,47 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"log"
+	"os"
 	"strings"
+	"time"
 
 	cli "github.com/washtubs/gojira-cli"
 )
 
+// Parses flags which may appear anywhere among the positional arguments,
+// returning the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	positional := make([]string, 0)
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// Collects issue keys from text given on the command line, and from stdin if requested
+func issueKeys(text string, stdin bool) []string {
+	keys := cli.ExtractIssueKeys(text)
+	if stdin {
+		stdinKeys, err := cli.ReadIssueKeys(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+		keys = append(keys, stdinKeys...)
+	}
+	return keys
+}
+
 func main() {
+	session := flag.String("session", "", "Name of the workbench session to resume or start")
 	flag.Parse()
-	if flag.Arg(0) == "_rpc" {
+	switch flag.Arg(0) {
+	case "_rpc":
 		c := cli.NewRpcClient()
 		action := flag.Arg(1)
 		switch action {
@@ -38,26 +69,191 @@ func main() {
 			log.Fatal("Unknown action " + action)
 		}
 		return
-	} else if flag.Arg(0) == "get" {
-		usage := "gojira-cli get ACME-12345"
-		issueId := flag.Arg(1)
-		if issueId == "" {
+	case "get":
+		usage := "gojira-cli get ACME-12345... [--stdin]"
+		fs := flag.NewFlagSet("get", flag.ExitOnError)
+		stdin := fs.Bool("stdin", false, "Read issue keys from arbitrary text on stdin")
+		args := parseArgs(fs, flag.Args()[1:])
+		keys := issueKeys(strings.Join(args, " "), *stdin)
+		if len(keys) == 0 {
 			log.Fatal(usage)
 		}
 		app := cli.NewApp()
-		jiraClientFactory := cli.NewJiraClientFactory(app)
-		client, err := jiraClientFactory.GetClient()
+		err := cli.RunGet(app, keys)
+		app.Close()
 		if err != nil {
 			log.Fatal(err)
 		}
-		issue, _, err := client.Issue.Get(issueId, nil)
+		return
+	case "act":
+		usage := "gojira-cli act ACTION [PARAM...] [--keys ACME-1,ACME-2] [--stdin] [--yes]"
+		fs := flag.NewFlagSet("act", flag.ExitOnError)
+		stdin := fs.Bool("stdin", false, "Read issue keys from arbitrary text on stdin")
+		keysFlag := fs.String("keys", "", "Issue keys to act on")
+		yes := fs.Bool("yes", false, "Don't ask for confirmation")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) == 0 {
+			log.Fatal(usage)
+		}
+		keys := issueKeys(*keysFlag, *stdin)
+		app := cli.NewApp()
+		err := cli.RunAct(app, args[0], args[1:], keys, *yes)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "active":
+		usage := "gojira-cli active [status|start KEY|review [KEY]|complete [KEY]|clear] [-m comment] [--yes]"
+		fs := flag.NewFlagSet("active", flag.ExitOnError)
+		comment := fs.String("m", "", "Comment for actions that leave one")
+		yes := fs.Bool("yes", false, "Don't ask for confirmation")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) > 2 {
+			log.Fatal(usage)
+		}
+		command, key := "", ""
+		if len(args) > 0 {
+			command = args[0]
+		}
+		if len(args) > 1 {
+			key = args[1]
+		}
+		app := cli.NewApp()
+		err := cli.RunActive(app, command, key, *comment, *yes)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "timer":
+		usage := "gojira-cli timer [status|start TARGET|KEY|stop [--comment text]]"
+		fs := flag.NewFlagSet("timer", flag.ExitOnError)
+		comment := fs.String("comment", "", "Work description for the logged time")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) > 2 {
+			log.Fatal(usage)
+		}
+		command, key := "", ""
+		if len(args) > 0 {
+			command = args[0]
+		}
+		if len(args) > 1 {
+			key = args[1]
+		}
+		app := cli.NewApp()
+		err := cli.RunTimer(app, command, key, *comment)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "log":
+		usage := "gojira-cli log [TARGET|KEY [DURATION [COMMENT...]]]"
+		args := flag.Args()[1:]
+		target, duration, comment := "", "", ""
+		if len(args) > 0 {
+			target = args[0]
+		}
+		if len(args) > 1 {
+			duration = args[1]
+		}
+		if len(args) > 2 {
+			comment = strings.Join(args[2:], " ")
+		}
+		app := cli.NewApp()
+		err := cli.RunLog(app, target, duration, comment)
+		app.Close()
+		if err != nil {
+			log.Fatal(err, "\n", usage)
+		}
+		return
+	case "sessions":
+		usage := "gojira-cli sessions [list|rm NAME]"
+		args := flag.Args()[1:]
+		if len(args) > 2 {
+			log.Fatal(usage)
+		}
+		command, name := "", ""
+		if len(args) > 0 {
+			command = args[0]
+		}
+		if len(args) > 1 {
+			name = args[1]
+		}
+		app := cli.NewApp()
+		err := cli.RunSessions(app, command, name)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "apply":
+		usage := "gojira-cli apply PLAN [--force] [--yes]"
+		fs := flag.NewFlagSet("apply", flag.ExitOnError)
+		force := fs.Bool("force", false, "Apply even if issues changed since the plan was created")
+		yes := fs.Bool("yes", false, "Don't ask for confirmation")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) != 1 {
+			log.Fatal(usage)
+		}
+		app := cli.NewApp()
+		err := cli.RunApply(app, args[0], *force, *yes)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "history":
+		usage := "gojira-cli history [--issue KEY] [--action ACTION] [--since DATE] [--until DATE] [--failed]"
+		fs := flag.NewFlagSet("history", flag.ExitOnError)
+		issue := fs.String("issue", "", "Only changes of this issue")
+		action := fs.String("action", "", "Only this action, e.g. addLabel")
+		since := fs.String("since", "", "Only changes on or after this date, as YYYY-MM-DD")
+		until := fs.String("until", "", "Only changes on or before this date, as YYYY-MM-DD")
+		failed := fs.Bool("failed", false, "Only failed changes")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) != 0 {
+			log.Fatal(usage)
+		}
+		filter := cli.AuditFilter{Key: *issue, Action: *action, Failed: *failed}
+		var err error
+		if *since != "" {
+			filter.Since, err = time.ParseInLocation("2006-01-02", *since, time.Local)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		if *until != "" {
+			filter.Until, err = time.ParseInLocation("2006-01-02", *until, time.Local)
+			if err != nil {
+				log.Fatal(err)
+			}
+			filter.Until = filter.Until.AddDate(0, 0, 1)
+		}
+		app := cli.NewApp()
+		err = cli.RunHistory(app, filter)
+		app.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "revert":
+		usage := "gojira-cli revert [--yes]"
+		fs := flag.NewFlagSet("revert", flag.ExitOnError)
+		yes := fs.Bool("yes", false, "Don't ask for confirmation")
+		args := parseArgs(fs, flag.Args()[1:])
+		if len(args) != 0 {
+			log.Fatal(usage)
+		}
+		app := cli.NewApp()
+		err := cli.RunRevert(app, *yes)
+		app.Close()
 		if err != nil {
 			log.Fatal(err)
 		}
-		fmt.Printf("%s: %s\n", issueId, issue.Fields.Summary)
 		return
-
 	}
 
-	cli.RunWorkbench()
+	cli.RunWorkbench(*session)
 }
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Fields which change without the issue itself being changed
+var ignoredChangedFields = map[string]bool{
+	"updated":    true,
+	"lastViewed": true,
+}
+
+// An issue which changed since it was loaded
+type StaleIssue struct {
+	Loaded  jira.Issue
+	Current *jira.Issue
+	// Names of the fields which differ
+	Fields []string
+}
+
+func (s StaleIssue) Format() string {
+	if s.Current == nil {
+		return s.Loaded.Key + ": no longer found"
+	}
+	updated := time.Time(s.Current.Fields.Updated).Format(time.RFC1123)
+	if len(s.Fields) == 0 {
+		return s.Loaded.Key + ": updated " + updated
+	}
+	return s.Loaded.Key + ": updated " + updated + ", changed " + strings.Join(s.Fields, ", ")
+}
+
+// Returns whether the current copy of an issue was updated after the loaded one,
+// and which fields differ
+func issueChanges(loaded, current jira.Issue) (bool, []string) {
+	if loaded.Fields == nil || current.Fields == nil {
+		return false, nil
+	}
+	if time.Time(current.Fields.Updated).Equal(time.Time(loaded.Fields.Updated)) {
+		return false, nil
+	}
+
+	loadedFields, err := fieldValues(loaded.Fields)
+	if err != nil {
+		return true, nil
+	}
+	currentFields, err := fieldValues(current.Fields)
+	if err != nil {
+		return true, nil
+	}
+
+	changed := make([]string, 0)
+	for name, value := range currentFields {
+		if !ignoredChangedFields[name] && !bytes.Equal(loadedFields[name], value) {
+			changed = append(changed, name)
+		}
+	}
+	// Fields which were emptied are omitted from the current copy
+	for name := range loadedFields {
+		if _, prs := currentFields[name]; !prs && !ignoredChangedFields[name] {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return true, changed
+}
+
+func fieldValues(fields *jira.IssueFields) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &values)
+	return values, err
+}
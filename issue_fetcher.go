@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Maximum number of keys that go into a single "key in (...)" clause
+const fetchBatchSize = 50
+
+// Matches a trailing ORDER BY clause, which can't be nested in parentheses
+var jqlOrderByRegexp = regexp.MustCompile(`(?is)\s*\border\s+by\s.*$`)
+
+// Fetches known issues by key using batched searches rather than one GET per key
+type IssueFetcher struct {
+	enumerator IssueEnumerator
+}
+
+// Returns the issues in the order the keys were given,
+// along with the keys for which no issue was found.
+// Issues which were moved are returned under their new key, with a notice
+func (f *IssueFetcher) FetchByKeys(keys []string) ([]jira.Issue, []string, error) {
+	byKey := make(map[string]jira.Issue)
+	// Moved issues by the old key they were requested by
+	moved := make(map[string]jira.Issue)
+
+	for start := 0; start < len(keys); start += fetchBatchSize {
+		end := start + fetchBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := keys[start:end]
+		requested := make(map[string]bool)
+		for _, key := range batch {
+			requested[key] = true
+		}
+
+		// Issues which came back under a key which wasn't requested
+		extra := make([]jira.Issue, 0)
+		err := f.enumerator.ForEachIssue(keysJql(batch), keysSearchOptions(), func(issue jira.Issue) error {
+			if _, prs := requested[issue.Key]; prs {
+				byKey[issue.Key] = issue
+			} else {
+				// The issue was moved and the old key still resolves
+				extra = append(extra, issue)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(extra) == 0 {
+			continue
+		}
+
+		unresolved := make([]string, 0)
+		for _, key := range batch {
+			if _, prs := byKey[key]; !prs {
+				unresolved = append(unresolved, key)
+			}
+		}
+		err = f.resolveMoved(unresolved, extra, moved)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	issues := make([]jira.Issue, 0, len(keys))
+	missing := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		issue, prs := byKey[key]
+		if !prs {
+			issue, prs = moved[key]
+			if !prs {
+				missing = append(missing, key)
+				continue
+			}
+			fmt.Printf("Issue %s was moved to %s\n", key, issue.Key)
+		}
+		// Both the old and the new key may have been given
+		if !seen[issue.ID] {
+			seen[issue.ID] = true
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues, missing, nil
+}
+
+func keysJql(keys []string) string {
+	return "key in (" + strings.Join(keys, ", ") + ")"
+}
+
+func keysSearchOptions() *jira.SearchOptions {
+	return &jira.SearchOptions{
+		MaxResults: fetchBatchSize,
+		// Nonexistent keys are reported as warnings rather than failing the whole search
+		ValidateQuery: "warn",
+	}
+}
+
+func (f *IssueFetcher) searchKeys(keys []string) ([]jira.Issue, error) {
+	found := make([]jira.Issue, 0)
+	err := f.enumerator.ForEachIssue(keysJql(keys), keysSearchOptions(), func(issue jira.Issue) error {
+		found = append(found, issue)
+		return nil
+	})
+	return found, err
+}
+
+// Maps old keys to the moved issues found by searching for them.
+// A search doesn't tell which key an issue was found by, so the keys are searched
+// in halves until each search finds a single issue. Halves without any moved issue,
+// like those of mistyped or deleted keys, take a single search
+func (f *IssueFetcher) resolveMoved(keys []string, found []jira.Issue, moved map[string]jira.Issue) error {
+	if len(found) == 0 {
+		return nil
+	}
+	if len(keys) == 1 {
+		moved[keys[0]] = found[0]
+		return nil
+	}
+	mid := len(keys) / 2
+	firstFound, err := f.searchKeys(keys[:mid])
+	if err != nil {
+		return err
+	}
+	err = f.resolveMoved(keys[:mid], firstFound, moved)
+	if err != nil {
+		return err
+	}
+
+	// What the first half didn't find, the second half did
+	switch len(firstFound) {
+	case len(found):
+		return nil
+	case 0:
+		return f.resolveMoved(keys[mid:], found, moved)
+	}
+	secondFound, err := f.searchKeys(keys[mid:])
+	if err != nil {
+		return err
+	}
+	return f.resolveMoved(keys[mid:], secondFound, moved)
+}
+
+// Returns all issues matching the jql, or only those among keys if keys is non-nil
+func (f *IssueFetcher) Search(jql string, keys []string) ([]jira.Issue, error) {
+	issues := make([]jira.Issue, 0)
+	collect := func(issue jira.Issue) error {
+		issues = append(issues, issue)
+		return nil
+	}
+
+	if keys == nil {
+		err := f.enumerator.ForEachIssue(jql, &jira.SearchOptions{MaxResults: fetchBatchSize}, collect)
+		return issues, err
+	}
+
+	jql = jqlOrderByRegexp.ReplaceAllString(jql, "")
+	for start := 0; start < len(keys); start += fetchBatchSize {
+		end := start + fetchBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batchJql := "key in (" + strings.Join(keys[start:end], ", ") + ")"
+		// A blank query matches everything, and "()" isn't valid JQL
+		if strings.TrimSpace(jql) != "" {
+			batchJql = "(" + jql + ") AND " + batchJql
+		}
+		opts := &jira.SearchOptions{
+			MaxResults:    fetchBatchSize,
+			ValidateQuery: "warn",
+		}
+		err := f.enumerator.ForEachIssue(batchJql, opts, collect)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return issues, nil
+}
+
+func NewIssueFetcher(jiraClientFactory *JiraClientFactory) *IssueFetcher {
+	return &IssueFetcher{&jiraIssueEnum{jiraClientFactory}}
+}
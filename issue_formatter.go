@@ -21,12 +21,20 @@ func (f IssueFormattable) Format() string {
 type IssueFormatter interface {
 	ExtractTicketId(formatted string) string
 	Format(issue jira.Issue) string
+	// Indexes of the issues in the order they should be shown
+	Order(issues []jira.Issue) []int
+	// The group the issue is shown under in working set views, empty if not grouping
+	Group(issue jira.Issue) string
 }
 
 type FormatterConfig struct {
 	excludeSummary  bool
 	includeLabels   bool
 	includeReporter bool
+	// One of issueOrderFields, or empty to keep the order issues were loaded in
+	sortBy string
+	// One of issueOrderFields, or empty not to group
+	groupBy string
 }
 
 var issueFormatterFlags = []string{
@@ -94,6 +102,49 @@ func (m *FormatterMenu) Select() error {
 	return nil
 }
 
+// Chooses what issues are sorted and grouped by
+func (m *FormatterMenu) SelectOrder() error {
+	options := []string{"Sort by (" + orderLabel(m.sortBy) + ")", "Group by (" + orderLabel(m.groupBy) + ")"}
+	p := promptui.Select{
+		Label: "Sort or group issues",
+		Items: options,
+		Size:  10,
+	}
+	which, _, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	items := append([]string{"none"}, issueOrderFields...)
+	p = promptui.Select{
+		Label: options[which],
+		Items: items,
+		Size:  10,
+	}
+	cursor, _, err := p.Run()
+	if err != nil {
+		return err
+	}
+	field := ""
+	if cursor > 0 {
+		field = items[cursor]
+	}
+
+	if which == 0 {
+		m.sortBy = field
+	} else {
+		m.groupBy = field
+	}
+	return nil
+}
+
+func orderLabel(field string) string {
+	if field == "" {
+		return "none"
+	}
+	return field
+}
+
 type defaultIssueFormatter struct {
 	*FormatterConfig
 }
@@ -105,9 +156,7 @@ func (f *defaultIssueFormatter) ExtractTicketId(formatted string) string {
 
 func (f *defaultIssueFormatter) Format(issue jira.Issue) string {
 
-	out := issue.ID
-
-	out = out + " " + issue.Key + " -"
+	out := issue.ID + " " + issue.Key + " -"
 
 	if !f.excludeSummary {
 		out = out + " " + issue.Fields.Summary
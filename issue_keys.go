@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"io"
+	"io/ioutil"
+	"regexp"
+)
+
+// Matches anything that looks like an issue key, e.g. ACME-123
+var issueKeyRegexp = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+func IsIssueKey(s string) bool {
+	loc := issueKeyRegexp.FindStringIndex(s)
+	return loc != nil && loc[0] == 0 && loc[1] == len(s)
+}
+
+// Extracts every distinct issue key from arbitrary text in the order they first appear
+func ExtractIssueKeys(text string) []string {
+	seen := make(map[string]bool)
+	keys := make([]string, 0)
+	for _, key := range issueKeyRegexp.FindAllString(text, -1) {
+		if _, prs := seen[key]; prs {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func ReadIssueKeys(r io.Reader) ([]string, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ExtractIssueKeys(string(bs)), nil
+}
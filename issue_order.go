@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+)
+
+// Fields the working set can be sorted and grouped by
+var issueOrderFields = []string{
+	"status",
+	"priority",
+	"assignee",
+	"updated",
+	"created",
+	"key",
+}
+
+// Value shown for the group an issue is in
+func issueGroup(issue jira.Issue, field string) string {
+	f := issue.Fields
+	if f == nil {
+		return "None"
+	}
+	switch field {
+	case "status":
+		if f.Status != nil {
+			return f.Status.Name
+		}
+	case "priority":
+		if f.Priority != nil {
+			return f.Priority.Name
+		}
+	case "assignee":
+		if f.Assignee != nil {
+			return f.Assignee.DisplayName
+		}
+		return "Unassigned"
+	case "updated":
+		return time.Time(f.Updated).Format("2006-01-02")
+	case "created":
+		return time.Time(f.Created).Format("2006-01-02")
+	case "key":
+		return issueProject(issue.Key)
+	}
+	return "None"
+}
+
+func issueProject(key string) string {
+	return strings.SplitN(key, "-", 2)[0]
+}
+
+func issueNumber(key string) int {
+	parts := strings.SplitN(key, "-", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[1])
+	return n
+}
+
+// Orders a before b by the field. Recent dates come first
+func issueLess(a, b jira.Issue, field string) bool {
+	if a.Fields == nil || b.Fields == nil {
+		return a.Fields != nil
+	}
+	switch field {
+	case "priority":
+		// Lower IDs are the higher priorities, and issues without one go last
+		if a.Fields.Priority == nil || b.Fields.Priority == nil {
+			return a.Fields.Priority != nil
+		}
+		ai, _ := strconv.Atoi(a.Fields.Priority.ID)
+		bi, _ := strconv.Atoi(b.Fields.Priority.ID)
+		return ai < bi
+	case "updated":
+		return time.Time(a.Fields.Updated).After(time.Time(b.Fields.Updated))
+	case "created":
+		return time.Time(a.Fields.Created).After(time.Time(b.Fields.Created))
+	case "key":
+		if issueProject(a.Key) != issueProject(b.Key) {
+			return issueProject(a.Key) < issueProject(b.Key)
+		}
+		return issueNumber(a.Key) < issueNumber(b.Key)
+	default:
+		return issueGroup(a, field) < issueGroup(b, field)
+	}
+}
+
+func (c *FormatterConfig) Group(issue jira.Issue) string {
+	if c.groupBy == "" {
+		return ""
+	}
+	return issueGroup(issue, c.groupBy)
+}
+
+// Returns the indexes of issues in display order: by group first, then by the sort field.
+// Otherwise issues keep their order
+func (c *FormatterConfig) Order(issues []jira.Issue) []int {
+	idxs := make([]int, len(issues))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		a, b := issues[idxs[i]], issues[idxs[j]]
+		if c.groupBy != "" {
+			if ag, bg := issueGroup(a, c.groupBy), issueGroup(b, c.groupBy); ag != bg {
+				return issueLess(a, b, c.groupBy)
+			}
+		}
+		if c.sortBy != "" {
+			return issueLess(a, b, c.sortBy)
+		}
+		return false
+	})
+	return idxs
+}
@@ -66,6 +66,36 @@ func (s *IssueSearchService) SearchInteractive(opts SelectOptions, useQueryRunne
 	return selected, nil
 }
 
+// Asks for a JQL, either one of the configured queries or one typed in
+func (s *IssueSearchService) SelectQueryInteractive() (string, error) {
+	p := promptui.Select{
+		Label: "Choose a query",
+		Items: []string{"Configured query", "Ad-hoc query"},
+		Size:  10,
+	}
+	cursor, _, err := p.Run()
+	if err != nil {
+		return "", err
+	}
+
+	if cursor == 1 {
+		jql, err := s.menuService.Prompt("JQL", "")
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(jql) == "" {
+			return "", CancelError()
+		}
+		return jql, nil
+	}
+
+	jqlKey, err := s.menuService.SelectJQL()
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(config.JQLs[jqlKey], "\n", " "), nil
+}
+
 func NewIssueSearchService(
 	searcher IssueSearcher,
 	menuService *MenuService,
@@ -104,10 +134,12 @@ func (m *IssueSearchMenu) Select() error {
 
 }
 
-func (m *IssueSearchMenu) Search(prompt string) (jira.Issue, error) {
+// The query pre-fills the selection, e.g. with the key of the issue selected before
+func (m *IssueSearchMenu) Search(prompt string, query string) (jira.Issue, error) {
 	opts := SelectOptions{
 		Prompt: prompt,
 		One:    true,
+		Query:  query,
 	}
 	var (
 		selected []jira.Issue
@@ -41,16 +41,43 @@ func (f *fixedSearchInteractor) Append(issue jira.Issue) { panic("not implemente
 func (f *fixedSearchInteractor) LoadResults()            {}
 func (f *fixedSearchInteractor) CloseSearch()            {}
 
+// Shows the group of each issue after it, leaving the usual layout intact
+type groupedIssueFormatter struct {
+	IssueFormatter
+}
+
+func (f groupedIssueFormatter) Format(issue jira.Issue) string {
+	out := f.IssueFormatter.Format(issue)
+	if group := f.Group(issue); group != "" {
+		out = out + " (" + group + ")"
+	}
+	return out
+}
+
+// Selects from the working set, shown in the formatter's order and with their groups.
+// The returned indexes are into issues
 func (s *IssueSelector) SelectSlc(issues []jira.Issue, opts SelectOptions) ([]int, bool, error) {
+	order := s.formatter.Order(issues)
+	ordered := make([]jira.Issue, len(issues))
 	issueChan := make(chan jira.Issue, len(issues))
-	for _, issue := range issues {
-		issueChan <- issue
+	for i, idx := range order {
+		ordered[i] = issues[idx]
+		issueChan <- issues[idx]
 	}
 	close(issueChan)
-	return s.Select(issueChan, &fixedSearchInteractor{issues}, opts)
+
+	idxs, canceled, err := s.selectWith(groupedIssueFormatter{s.formatter}, issueChan, &fixedSearchInteractor{ordered}, opts)
+	for i, idx := range idxs {
+		idxs[i] = order[idx]
+	}
+	return idxs, canceled, err
 }
 
 func (s *IssueSelector) Select(issues <-chan jira.Issue, interactor SearchInteractor, opts SelectOptions) ([]int, bool, error) {
+	return s.selectWith(s.formatter, issues, interactor, opts)
+}
+
+func (s *IssueSelector) selectWith(formatter IssueFormatter, issues <-chan jira.Issue, interactor SearchInteractor, opts SelectOptions) ([]int, bool, error) {
 	port, err := ListenRpc(interactor)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "Failed to start listening to RPC")
@@ -58,7 +85,7 @@ func (s *IssueSelector) Select(issues <-chan jira.Issue, interactor SearchIntera
 	defer StopListenRpc(port)
 
 	opts.Exclude = 1 // we include the ID in the formatter
-	idxs, canceled, err := FzfSelectChan(mapIssueChan(issues, s.formatter), opts, port)
+	idxs, canceled, err := FzfSelectChan(mapIssueChan(issues, formatter), opts, port)
 	if err != nil {
 		return nil, false, errors.Wrap(err, "Failed to get FZF results for issue selection")
 	}
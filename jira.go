@@ -13,8 +13,10 @@ import (
 )
 
 type JiraClientFactory struct {
-	config *Config
-	client *jira.Client
+	config      *Config
+	client      *jira.Client
+	currentUser *jira.User
+	rateLimiter *RateLimiter
 }
 
 func (j *JiraClientFactory) GetClient() (*jira.Client, error) {
@@ -30,15 +32,61 @@ func (j *JiraClientFactory) GetClient() (*jira.Client, error) {
 
 	tp := jira.PATAuthTransport{
 		Token:     strings.TrimSpace(string(token)),
-		Transport: http.DefaultTransport,
+		Transport: &retryTransport{&rateLimitTransport{http.DefaultTransport, j.rateLimiter}},
 	}
 	j.client, err = jira.NewClient(tp.Client(), config.Url)
 
 	return j.client, err
 }
 
+// The user the client is authenticated as.
+// If client.username is configured only the name is known
+func (j *JiraClientFactory) CurrentJiraUser() (*jira.User, error) {
+	if j.currentUser != nil {
+		return j.currentUser, nil
+	}
+	if j.config.Client.Username != "" {
+		j.currentUser = &jira.User{Name: j.config.Client.Username}
+		return j.currentUser, nil
+	}
+
+	client, err := j.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	user, resp, err := client.User.GetSelf()
+	LogHttpResponse(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get current user")
+	}
+	j.currentUser = user
+	return j.currentUser, nil
+}
+
+// Identifies the current user by account ID, since Jira Cloud has no user names,
+// falling back to the name and then the display name
+func (j *JiraClientFactory) CurrentUser() (string, error) {
+	user, err := j.CurrentJiraUser()
+	if err != nil {
+		return "", err
+	}
+	for _, id := range []string{user.AccountID, user.Name, user.DisplayName} {
+		if id != "" {
+			return id, nil
+		}
+	}
+	return "", errors.New("Jira didn't identify the current user")
+}
+
+func (j *JiraClientFactory) Close() {
+	j.rateLimiter.Stop()
+}
+
 func NewJiraClientFactory(app *App) *JiraClientFactory {
-	return &JiraClientFactory{config: app.config}
+	return &JiraClientFactory{
+		config:      app.config,
+		rateLimiter: NewRateLimiter(app.config.Client.RateLimit),
+	}
 }
 
 type IssueLinkTypeMenu struct {
@@ -53,7 +101,8 @@ func (m *IssueLinkTypeMenu) IssueLinkType() (linkType jira.IssueLinkType, subjec
 	return
 }
 
-func (m *IssueLinkTypeMenu) Select(subjectIssue jira.Issue) error {
+// Starts at the current link type and direction, if there is one
+func (m *IssueLinkTypeMenu) Select(subjectIssue jira.Issue, current jira.IssueLinkType, subjectIsInward bool) error {
 	if m.issueLinkTypes == nil {
 		client, err := m.jiraClientFactory.GetClient()
 		if err != nil {
@@ -79,6 +128,15 @@ func (m *IssueLinkTypeMenu) Select(subjectIssue jira.Issue) error {
 		}
 	}
 
+	for i, link := range m.issueLinkTypes {
+		if current.Name != "" && link.Name == current.Name {
+			m.cursor = i * 2
+			if subjectIsInward {
+				m.cursor++
+			}
+		}
+	}
+
 	labels := make([]string, len(m.issueLinkTypes)*2)
 	for i, link := range m.issueLinkTypes {
 		labels[i*2] = subjectIssue.Key + " - " + link.Inward + "..."
@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	journalDir = "journal"
+	// Older executions are forgotten
+	maxJournaled = 50
+	// Sorts the same lexically and chronologically
+	executionIdFormat = "20060102T150405.000000000"
+)
+
+// Records an executed action along with what it takes to revert it
+type JournalEntry struct {
+	Key string `json:"key"`
+	// Identifies the issue even after it was moved. Empty in older journals
+	ID     string     `json:"id,omitempty"`
+	Action ActionSpec `json:"action"`
+	// False for actions like shell commands, which can't be reverted at all
+	Revertible bool `json:"revertible"`
+	// The actions which revert the change, empty if nothing was changed
+	Inverse []ActionSpec `json:"inverse,omitempty"`
+}
+
+// The changes made by one execution of the executor
+type Execution struct {
+	ID      string    `json:"id"`
+	Started time.Time `json:"started"`
+	// Set if this execution reverted another
+	RevertOf string         `json:"revertOf,omitempty"`
+	Reverted bool           `json:"reverted"`
+	Entries  []JournalEntry `json:"entries"`
+}
+
+// Whether anything is left to revert
+func (e *Execution) Revertible() bool {
+	for _, entry := range e.Entries {
+		if entry.Revertible && len(entry.Inverse) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Keeps the changes made by recent executions under $XDG_STATE_HOME/gojira-cli/journal
+type Journal struct{}
+
+func executionFile(id string) string {
+	return filepath.Join(journalDir, id+".json")
+}
+
+func (j *Journal) Begin(revertOf string) *Execution {
+	started := time.Now()
+	return &Execution{
+		ID:       started.UTC().Format(executionIdFormat),
+		Started:  started,
+		RevertOf: revertOf,
+		Entries:  make([]JournalEntry, 0),
+	}
+}
+
+// Adds the entry and saves the execution right away, so nothing is lost if we crash
+func (j *Journal) Record(e *Execution, entry JournalEntry) error {
+	first := len(e.Entries) == 0
+	e.Entries = append(e.Entries, entry)
+	err := j.Save(e)
+	if err == nil && first {
+		err = j.prune()
+	}
+	return err
+}
+
+func (j *Journal) Save(e *Execution) error {
+	return saveState(executionFile(e.ID), e)
+}
+
+func (j *Journal) ids() ([]string, error) {
+	files, err := listState(journalDir)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(files))
+	for _, file := range files {
+		if strings.HasSuffix(file, ".json") {
+			ids = append(ids, strings.TrimSuffix(file, ".json"))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (j *Journal) prune() error {
+	ids, err := j.ids()
+	if err != nil {
+		return err
+	}
+	for len(ids) > maxJournaled {
+		err = removeState(executionFile(ids[0]))
+		if err != nil {
+			return err
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// Returns the most recent execution which has something left to revert,
+// and isn't a revert itself, or nil if there is none
+func (j *Journal) LastRevertible() (*Execution, error) {
+	ids, err := j.ids()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		e := &Execution{}
+		_, err := loadState(executionFile(ids[i]), e)
+		if err != nil {
+			return nil, err
+		}
+		if e.RevertOf == "" && !e.Reverted && e.Revertible() {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+// Reverts executions using the journal
+type RevertService struct {
+	journal           *Journal
+	issueFetcher      *IssueFetcher
+	actionBaseService *ActionBaseService
+	executorService   *ExecutorService
+	menuService       *MenuService
+}
+
+// Builds and runs the inverse actions of the last execution, after a preview.
+// Entries which could not be reverted remain revertible
+func (s *RevertService) RevertLast(assumeYes bool) error {
+	execution, err := s.journal.LastRevertible()
+	if err != nil {
+		return err
+	}
+	if execution == nil {
+		return errors.New("Nothing to revert")
+	}
+	fmt.Printf("Reverting the execution from %s\n", execution.Started.Format(time.RFC1123))
+
+	// Revert the most recent changes first
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	for i := len(execution.Entries) - 1; i >= 0; i-- {
+		entry := execution.Entries[i]
+		if !entry.Revertible {
+			fmt.Printf("Can't be reverted: %s on %s\n", strings.Join(entry.Action, " "), entry.Key)
+			continue
+		}
+		if len(entry.Inverse) > 0 && !seen[entry.Key] {
+			seen[entry.Key] = true
+			keys = append(keys, entry.Key)
+		}
+	}
+
+	issues, err := fetchIssuesReportMissing(s.issueFetcher, keys)
+	if err != nil {
+		return err
+	}
+	// Moved issues come back under their new key, so they are matched by ID
+	byId := make(map[string]int)
+	byKey := make(map[string]int)
+	for i, issue := range issues {
+		byId[issue.ID] = i
+		byKey[issue.Key] = i
+	}
+
+	actions := make([]IssueAction, 0)
+	// The entry each action reverts
+	entryIdxs := make([]int, 0)
+	notFound := 0
+	for i := len(execution.Entries) - 1; i >= 0; i-- {
+		entry := execution.Entries[i]
+		if !entry.Revertible || len(entry.Inverse) == 0 {
+			continue
+		}
+		var (
+			idx int
+			prs bool
+		)
+		if entry.ID != "" {
+			idx, prs = byId[entry.ID]
+		} else {
+			idx, prs = byKey[entry.Key]
+		}
+		if !prs {
+			fmt.Printf("Can't revert %s on %s, the issue wasn't found\n", strings.Join(entry.Action, " "), entry.Key)
+			notFound++
+			continue
+		}
+		for _, spec := range entry.Inverse {
+			actionBase, err := s.actionBaseService.BuildRevertActionParams(spec[0], spec[1:])
+			if err != nil {
+				return errors.Wrapf(err, "Failed to build the inverse of %s", strings.Join(entry.Action, " "))
+			}
+			actions = append(actions, IssueAction{issues[idx], actionBase})
+			entryIdxs = append(entryIdxs, i)
+		}
+	}
+
+	if !assumeYes {
+		s.executorService.Execute(actions, true)
+		if !s.menuService.Confirm(fmt.Sprintf("Revert with %d action(s)", len(actions))) {
+			return CancelError()
+		}
+	}
+
+	errs := s.executorService.ExecuteRevert(actions, execution.ID)
+
+	// Whatever was reverted must not be reverted again
+	failedEntries := make(map[int]bool)
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failedEntries[entryIdxs[i]] = true
+			failed++
+		}
+	}
+	for _, i := range entryIdxs {
+		if !failedEntries[i] {
+			execution.Entries[i].Inverse = nil
+		}
+	}
+	execution.Reverted = !execution.Revertible()
+	err = s.journal.Save(execution)
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d revert action(s) failed", failed, len(actions))
+	}
+	if notFound > 0 {
+		return errors.Errorf("%d change(s) not reverted, their issues weren't found", notFound)
+	}
+	return nil
+}
+
+func NewRevertService(
+	journal *Journal,
+	issueFetcher *IssueFetcher,
+	actionBaseService *ActionBaseService,
+	executorService *ExecutorService,
+	menuService *MenuService,
+) *RevertService {
+	return &RevertService{
+		journal,
+		issueFetcher,
+		actionBaseService,
+		executorService,
+		menuService,
+	}
+}
+
+func RunRevert(app *App, assumeYes bool) error {
+	return app.revertService.RevertLast(assumeYes)
+}
@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
 )
 
 type StaticMenu struct {
@@ -57,12 +59,121 @@ func (m *FzfMenu) Select() error {
 	return err
 }
 
+// The workbench menu, showing either the simple or the advanced items
+type WorkbenchMenu struct {
+	simple       []*MenuAction
+	advanced     []*MenuAction
+	advancedMode bool
+	// Last position in the list of each mode
+	simpleCursor   int
+	advancedCursor int
+}
+
+// Orders the actions by their keys, dropping those not listed.
+// The toggle is always kept so the other mode stays reachable
+func orderMenuActions(actions []*MenuAction, keys []string, toggle string) []*MenuAction {
+	byKey := make(map[string]*MenuAction, len(actions))
+	for _, action := range actions {
+		byKey[action.key] = action
+	}
+	ordered := make([]*MenuAction, 0, len(keys))
+	hasToggle := false
+	for _, key := range keys {
+		action, prs := byKey[key]
+		if !prs {
+			log.Printf("Unknown menu item %s", key)
+			continue
+		}
+		hasToggle = hasToggle || key == toggle
+		ordered = append(ordered, action)
+	}
+	if !hasToggle {
+		ordered = append(ordered, byKey[toggle])
+	}
+
+	// Only the first of several items with the same hotkey keeps it
+	hotkeys := make(map[string]bool)
+	for i, action := range ordered {
+		if action.hotkey == "" {
+			continue
+		}
+		if hotkeys[action.hotkey] {
+			log.Printf("Hotkey %s of %s is already taken", action.hotkey, action.key)
+			copied := *action
+			copied.hotkey = ""
+			ordered[i] = &copied
+			continue
+		}
+		hotkeys[action.hotkey] = true
+	}
+	return ordered
+}
+
+func (m *WorkbenchMenu) current() ([]*MenuAction, *int) {
+	if m.advancedMode {
+		return m.advanced, &m.advancedCursor
+	}
+	return m.simple, &m.simpleCursor
+}
+
+// Shows the items with their hotkeys and waits for one to be pressed.
+// Enter, or any failure to read a single key, brings up a list to choose from instead
+func (m *WorkbenchMenu) Select() (*MenuAction, error) {
+	actions, cursor := m.current()
+	for _, action := range actions {
+		hotkey := " "
+		if action.hotkey != "" {
+			hotkey = action.hotkey
+		}
+		fmt.Printf("  [%s] %s\n", hotkey, action.label)
+	}
+
+	for {
+		fmt.Print("Press a key (Enter for a list): ")
+		key, err := readKey()
+		fmt.Println()
+		if err != nil {
+			log.Printf("Failed to read a key: %s", err)
+			break
+		}
+		if key == '\r' || key == '\n' {
+			break
+		}
+		// Ctrl-C and Ctrl-D, which arrive as keys since signals are off while reading
+		if key == 3 || key == 4 {
+			return nil, CancelError()
+		}
+		for i, action := range actions {
+			if action.hotkey == string(key) {
+				*cursor = i
+				return action, nil
+			}
+		}
+		fmt.Printf("No item for key %q\n", key)
+	}
+
+	labels := make([]string, len(actions))
+	for i, action := range actions {
+		labels[i] = action.label
+	}
+	menu := &StaticMenu{
+		prompt:  "Select an option",
+		entries: labels,
+		cursor:  *cursor,
+	}
+	err := menu.Select()
+	if err != nil {
+		return nil, err
+	}
+	*cursor = menu.cursor
+	return actions[menu.cursor], nil
+}
+
 // Basically mediates access to the simple promts using promptui
 type MenuService struct {
 	config            *Config
 	jqlMenu           *FzfMenu
-	mainMenu          *StaticMenu
-	advancedMenu      *StaticMenu
+	workbenchMenu     *WorkbenchMenu
 	formatterMenu     *FormatterMenu
 	issueSearchMenu   *IssueSearchMenu
 	issueLinkTypeMenu *IssueLinkTypeMenu
@@ -81,34 +192,109 @@ func (s *MenuService) Comment(prompt string) string {
 	return comment
 }
 
+// Prompts for a line of text, pre-filled with defaultValue.
+// Reads from the terminal even if stdin was used for input
+func (s *MenuService) Prompt(prompt string, defaultValue string) (string, error) {
+	p := promptui.Prompt{
+		Label:     prompt,
+		Default:   defaultValue,
+		AllowEdit: true,
+	}
+	tty, err := openTty()
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to open terminal")
+	}
+	if tty != nil {
+		defer tty.Close()
+		p.Stdin = tty
+	}
+	return p.Run()
+}
+
+// Asks a yes/no question. Reads from the terminal even if stdin was used for input
+func (s *MenuService) Confirm(prompt string) bool {
+	p := promptui.Prompt{
+		Label:     prompt,
+		IsConfirm: true,
+	}
+	tty, err := openTty()
+	if err != nil {
+		log.Println("Failed to open terminal: " + err.Error())
+		return false
+	}
+	if tty != nil {
+		defer tty.Close()
+		p.Stdin = tty
+	}
+	_, err = p.Run()
+	return err == nil
+}
+
 // Interactively select a JQL key
 func (s *MenuService) SelectJQL() (string, error) {
 	err := s.jqlMenu.Select()
 	return keysFromMap(s.config.JQLs)[s.jqlMenu.cursor], err
 }
 
-func (s *MenuService) SelectMain() (int, error) {
-	s.mainMenu.cursor = 4
-	err := s.mainMenu.Select()
-	return s.mainMenu.cursor, err
+func (s *MenuService) SelectWorkbenchAction() (*MenuAction, error) {
+	return s.workbenchMenu.Select()
+}
+
+// Switches the workbench menu between simple and advanced mode
+func (s *MenuService) SetAdvancedMenu(advanced bool) {
+	s.workbenchMenu.advancedMode = advanced
 }
 
 func (s *MenuService) SelectIssueFormat() error {
 	return s.formatterMenu.Select()
 }
 
-func (s *MenuService) RegisterMainMenu(mainMenuActions []string) {
-	s.mainMenu = &StaticMenu{
-		prompt:  "Select an option",
-		entries: mainMenuActions,
+func (s *MenuService) SelectIssueOrder() error {
+	return s.formatterMenu.SelectOrder()
+}
+
+// Builds the workbench menu from the actions, as configured
+func (s *MenuService) RegisterWorkbenchMenu(actions []*MenuAction) {
+	menuConfig := s.config.Menu
+	if menuConfig == nil {
+		menuConfig = &MenuConfig{}
 	}
+	for _, action := range actions {
+		if hotkey, prs := menuConfig.Hotkeys[action.key]; prs {
+			if len(hotkey) > 1 {
+				log.Printf("Hotkey %s of %s must be a single character", hotkey, action.key)
+				continue
+			}
+			action.hotkey = hotkey
+		}
+	}
+
+	simple := menuConfig.Simple
+	if simple == nil {
+		simple = defaultSimpleMenu
+	}
+	advanced := menuConfig.Advanced
+	if advanced == nil {
+		advanced = defaultAdvancedMenu
+	}
+	s.workbenchMenu = &WorkbenchMenu{
+		simple:       orderMenuActions(actions, simple, "advanced"),
+		advanced:     orderMenuActions(actions, advanced, "simple"),
+		advancedMode: menuConfig.StartAdvanced,
+	}
+	// Start on the action that gets things done, so a reflexive Enter doesn't quit
+	s.workbenchMenu.simpleCursor = menuIndex(s.workbenchMenu.simple, "doIt")
+	s.workbenchMenu.advancedCursor = menuIndex(s.workbenchMenu.advanced, "execute")
 }
 
-func (s *MenuService) RegisterAdvancedMenu(advancedMenuActions []string) {
-	s.advancedMenu = &StaticMenu{
-		prompt:  "Select an option",
-		entries: advancedMenuActions,
+// The position of the action with the key, or the first if it isn't shown
+func menuIndex(actions []*MenuAction, key string) int {
+	for i, action := range actions {
+		if action.key == key {
+			return i
+		}
 	}
+	return 0
 }
 
 func (s *MenuService) RegisterFormatterMenu(formatterMenu *FormatterMenu) {
@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// A reviewable description of a bulk change, to be applied later
+type Plan struct {
+	// Issues updated after this are considered changed since the plan was made
+	Created time.Time    `yaml:"created"`
+	Actions []PlanAction `yaml:"actions"`
+}
+
+type PlanAction struct {
+	Action string   `yaml:"action"`
+	Params []string `yaml:"params,flow"`
+	Issues []string `yaml:"issues,flow"`
+}
+
+func (p PlanAction) Format() string {
+	return strings.TrimSpace(p.Action + " " + strings.Join(p.Params, " "))
+}
+
+// Describes the queue, with one entry per distinct action in the order they first appear
+func NewPlan(queue []IssueAction) *Plan {
+	plan := &Plan{
+		Created: time.Now(),
+		Actions: make([]PlanAction, 0),
+	}
+	idxs := make(map[string]int)
+	for _, issueAction := range queue {
+		canonical := canonicalAction(issueAction.action)
+		idx, prs := idxs[canonical]
+		if !prs {
+			idx = len(plan.Actions)
+			idxs[canonical] = idx
+			plan.Actions = append(plan.Actions, PlanAction{
+				Action: issueAction.action.Key(),
+				Params: issueAction.action.ToParams(),
+				Issues: make([]string, 0),
+			})
+		}
+		plan.Actions[idx].Issues = append(plan.Actions[idx].Issues, issueAction.issue.Key)
+	}
+	return plan
+}
+
+func WritePlan(path string, plan *Plan) error {
+	bs, err := yaml.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(path, bs, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Error writing plan to %s", path)
+	}
+	return nil
+}
+
+func ReadPlan(path string) (*Plan, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{}
+	err = yaml.Unmarshal(bs, plan)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to parse plan %s", path)
+	}
+	if plan.Created.IsZero() {
+		return nil, errors.Errorf("Plan %s has no created timestamp", path)
+	}
+	return plan, nil
+}
+
+// Rebuilds the actions of the plan against freshly fetched issues
+func buildPlan(app *App, plan *Plan) ([]IssueAction, []jira.Issue, []string, error) {
+	actionBases := make([]IssueActionBase, len(plan.Actions))
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	for i, planAction := range plan.Actions {
+		actionBase, err := app.actionBaseService.BuildActionParams(planAction.Action, planAction.Params)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "Invalid action %s", planAction.Format())
+		}
+		actionBases[i] = actionBase
+		for _, key := range planAction.Issues {
+			if _, prs := seen[key]; !prs {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	issues, missing, err := app.issueFetcher.FetchByKeys(keys)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	byKey := make(map[string]jira.Issue)
+	for _, issue := range issues {
+		byKey[issue.Key] = issue
+	}
+
+	actions := make([]IssueAction, 0)
+	for i, planAction := range plan.Actions {
+		for _, key := range planAction.Issues {
+			if issue, prs := byKey[key]; prs {
+				actions = append(actions, IssueAction{issue, actionBases[i]})
+			}
+		}
+	}
+	return actions, issues, missing, nil
+}
+
+// Executes a plan file. Refuses to if any issue changed since the plan was made,
+// or no longer exists, unless forced
+func RunApply(app *App, path string, force bool, assumeYes bool) error {
+	plan, err := ReadPlan(path)
+	if err != nil {
+		return err
+	}
+
+	actions, issues, missing, err := buildPlan(app, plan)
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, key := range missing {
+		fmt.Printf("Issue does not exist: %s\n", key)
+		problems++
+	}
+	for _, issue := range issues {
+		updated := time.Time(issue.Fields.Updated)
+		if updated.After(plan.Created) {
+			fmt.Printf("%s was updated at %s, after the plan was created\n", issue.Key, updated.Format(time.RFC1123))
+			problems++
+		}
+	}
+	if problems > 0 && !force {
+		return errors.Errorf("%d issue(s) changed since %s, use --force to apply anyway", problems, plan.Created.Format(time.RFC1123))
+	}
+
+	if len(actions) == 0 {
+		return errors.New("Nothing to do")
+	}
+	return confirmAndExecute(app.executorService, app.menuService, actions, assumeYes)
+}
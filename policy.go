@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	policyDeny    = "deny"
+	policyConfirm = "confirm"
+	// Stands for the favorite users in allowedUsers
+	favoriteUsers = "@favorites"
+)
+
+// Denies, or requires confirmation for, the actions matching all of its conditions.
+// Conditions left empty match everything
+type PolicyRule struct {
+	// Either deny or confirm
+	Effect string `yaml:"effect"`
+	Reason string `yaml:"reason"`
+	// Action keys, e.g. addComment
+	Actions  []string `yaml:"actions"`
+	Projects []string `yaml:"projects"`
+	Statuses []string `yaml:"statuses"`
+	// Matches actions assigning anyone else. May include @favorites
+	AllowedUsers []string `yaml:"allowedUsers"`
+}
+
+// Implemented by actions which make a user the target of a change, like assigning them
+type UserTargetAction interface {
+	TargetUser() string
+}
+
+func (a AssignUserAction) TargetUser() string { return a.UserName }
+
+// A rule matched by an action
+type PolicyViolation struct {
+	Effect string
+	Reason string
+}
+
+func (v PolicyViolation) Format() string {
+	if v.Effect == policyConfirm {
+		return "needs confirmation: " + v.Reason
+	}
+	return "denied: " + v.Reason
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Applies the configured rules to actions before they are executed
+type Policy struct {
+	rules     []PolicyRule
+	favorites *FavoritesService
+}
+
+func (p *Policy) matches(rule PolicyRule, ia IssueAction) bool {
+	if len(rule.Actions) > 0 && !containsFold(rule.Actions, ia.action.Key()) {
+		return false
+	}
+	if len(rule.Projects) > 0 {
+		project := issueProject(ia.issue.Key)
+		if ia.issue.Fields != nil && ia.issue.Fields.Project.Key != "" {
+			project = ia.issue.Fields.Project.Key
+		}
+		if !containsFold(rule.Projects, project) {
+			return false
+		}
+	}
+	if len(rule.Statuses) > 0 {
+		if ia.issue.Fields == nil || ia.issue.Fields.Status == nil ||
+			!containsFold(rule.Statuses, ia.issue.Fields.Status.Name) {
+			return false
+		}
+	}
+	if len(rule.AllowedUsers) > 0 {
+		target, ok := ia.action.(UserTargetAction)
+		if !ok {
+			return false
+		}
+		allowed := rule.AllowedUsers
+		if containsFold(allowed, favoriteUsers) {
+			allowed = append(append([]string{}, allowed...), p.favorites.Users()...)
+		}
+		if containsFold(allowed, target.TargetUser()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the violated rule, denying ones first, or nil if the action is allowed
+func (p *Policy) Check(ia IssueAction) *PolicyViolation {
+	var confirm *PolicyViolation
+	for _, rule := range p.rules {
+		if !p.matches(rule, ia) {
+			continue
+		}
+		reason := rule.Reason
+		if reason == "" {
+			reason = "policy rule"
+		}
+		if rule.Effect == policyConfirm {
+			if confirm == nil {
+				confirm = &PolicyViolation{policyConfirm, reason}
+			}
+			continue
+		}
+		return &PolicyViolation{policyDeny, reason}
+	}
+	return confirm
+}
+
+// Returns errors for the actions which are denied, or whose confirmation the user declines.
+// Asks once for all actions which need confirmation
+func (p *Policy) Enforce(actions []IssueAction, menuService *MenuService) []error {
+	errs := make([]error, len(actions))
+	needConfirm := make([]int, 0)
+	for i, ia := range actions {
+		violation := p.Check(ia)
+		if violation == nil {
+			continue
+		}
+		formatted := IssueActionFormatter{ia}.Format()
+		if violation.Effect == policyDeny {
+			errs[i] = errors.Errorf("Denied by policy: %s", violation.Reason)
+			fmt.Printf("Denied by policy: %s (%s)\n", formatted, violation.Reason)
+			continue
+		}
+		fmt.Printf("Needs confirmation by policy: %s (%s)\n", formatted, violation.Reason)
+		needConfirm = append(needConfirm, i)
+	}
+
+	if len(needConfirm) > 0 && !menuService.Confirm(fmt.Sprintf("Execute %d action(s) needing confirmation", len(needConfirm))) {
+		for _, i := range needConfirm {
+			errs[i] = errNotAttempted
+		}
+	}
+	return errs
+}
+
+func NewPolicy(rules []PolicyRule, favorites *FavoritesService) *Policy {
+	valid := make([]PolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Effect == "" {
+			rule.Effect = policyDeny
+		}
+		if rule.Effect != policyDeny && rule.Effect != policyConfirm {
+			// Better safe than sorry
+			log.Printf("Unknown policy effect %s, denying instead", rule.Effect)
+			rule.Effect = policyDeny
+		}
+		valid = append(valid, rule)
+	}
+	return &Policy{valid, favorites}
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultRequestsPerSecond = 2.0
+	defaultRateLimitBurst    = 2
+	// The slowest we get, however hard Jira pushes back
+	maxRateLimitInterval = 10 * time.Second
+	// Below this share of the server's remaining budget we slow down, above recoverShare we speed up again
+	slowDownShare = 0.2
+	recoverShare  = 0.5
+)
+
+// Hands out permits for requests at a steady rate, allowing a short burst.
+// The rate adapts to what Jira reports about its own rate limiting
+type RateLimiter struct {
+	permits  chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	mutex sync.Mutex
+	// The configured interval between requests
+	base time.Duration
+	// The shortest interval allowed, the base or how fast Jira refills its budget if slower
+	floor    time.Duration
+	interval time.Duration
+	warned   bool
+}
+
+// Waits for a permit, returning early if ctx is done or the limiter is stopped
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-r.permits:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-r.done:
+		return errors.New("Rate limiter stopped")
+	}
+}
+
+// Stops handing out permits. Safe to call more than once
+func (r *RateLimiter) Stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+func (r *RateLimiter) currentInterval() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.interval
+}
+
+func (r *RateLimiter) run() {
+	timer := time.NewTimer(r.currentInterval())
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+			select {
+			case r.permits <- struct{}{}:
+			default:
+			}
+			timer.Reset(r.currentInterval())
+		case <-r.done:
+			return
+		}
+	}
+}
+
+func (r *RateLimiter) setInterval(interval time.Duration) {
+	if interval > maxRateLimitInterval {
+		interval = maxRateLimitInterval
+	}
+	if interval < r.floor {
+		interval = r.floor
+	}
+	if interval != r.interval {
+		log.Printf("Rate limit now %s between requests", interval)
+	}
+	r.interval = interval
+}
+
+// Adjusts the rate to the rate limiting headers of a response
+func (r *RateLimiter) Observe(resp *http.Response) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	// The server refills FillRate requests every Interval-Seconds
+	fillRate, fillErr := strconv.ParseFloat(resp.Header.Get("X-RateLimit-FillRate"), 64)
+	seconds, secondsErr := strconv.ParseFloat(resp.Header.Get("X-RateLimit-Interval-Seconds"), 64)
+	if fillErr == nil && secondsErr == nil && fillRate > 0 && seconds > 0 {
+		r.floor = time.Duration(seconds / fillRate * float64(time.Second))
+		if r.floor < r.base {
+			r.floor = r.base
+		}
+		r.setInterval(r.interval)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		r.setInterval(maxRateLimitInterval)
+		return
+	}
+
+	switch resp.Header.Get("X-Seraph-LoginReason") {
+	case "AUTHENTICATION_DENIED", "AUTHENTICATED_FAILED":
+		// Further failed logins may lock the account, so back right off
+		if !r.warned {
+			log.Printf("Jira rejected the login (%s), slowing down requests", resp.Header.Get("X-Seraph-LoginReason"))
+			r.warned = true
+		}
+		r.setInterval(maxRateLimitInterval)
+		return
+	}
+
+	limit, limitErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	remaining, remainingErr := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if limitErr != nil || remainingErr != nil || limit <= 0 {
+		// Without a budget to go by, recover from earlier throttling as long as requests succeed
+		if resp.StatusCode < 400 && r.interval > r.floor {
+			r.setInterval(r.interval / 2)
+		}
+		return
+	}
+	share := float64(remaining) / float64(limit)
+	if share < slowDownShare {
+		r.setInterval(r.interval * 2)
+	} else if share >= recoverShare && r.interval > r.floor {
+		r.setInterval(r.interval / 2)
+	}
+}
+
+// Builds a rate limiter from the client config, using defaults for whatever isn't set
+func NewRateLimiter(config *RateLimitConfig) *RateLimiter {
+	requestsPerSecond := defaultRequestsPerSecond
+	burst := defaultRateLimitBurst
+	if config != nil {
+		if config.RequestsPerSecond > 0 {
+			requestsPerSecond = config.RequestsPerSecond
+		}
+		if config.Burst > 0 {
+			burst = config.Burst
+		}
+	}
+	base := time.Duration(float64(time.Second) / requestsPerSecond)
+
+	r := &RateLimiter{
+		permits:  make(chan struct{}, burst),
+		done:     make(chan struct{}),
+		base:     base,
+		floor:    base,
+		interval: base,
+	}
+	go r.run()
+	return r
+}
+
+// Paces every request, including retries, and lets the rate limiter see every response
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *RateLimiter
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil {
+		t.limiter.Observe(resp)
+	}
+	return resp, err
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	maxRetries       = 4
+	retryBaseDelay   = time.Second
+	retryMaxDelay    = 30 * time.Second
+	retryAfterCutoff = 5 * time.Minute
+)
+
+// Statuses which mean the request wasn't processed and may succeed if sent again
+var retryableStatuses = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// Methods which can be sent again without repeating their effect.
+// Notably not POST, which creates comments, worklogs and links and does transitions
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// Retries idempotent requests which Jira rejected as throttled or temporarily unavailable,
+// with exponential backoff and jitter unless Jira says how long to wait
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !idempotentMethods[req.Method] {
+		return t.base.RoundTrip(req)
+	}
+
+	// Round trippers mustn't modify the request, so each retry is sent as a copy
+	attemptReq := req
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil || !retryableStatuses[resp.StatusCode] || attempt > maxRetries {
+			return resp, err
+		}
+
+		delay, ok := retryAfter(resp)
+		if !ok {
+			delay = backoff(attempt)
+		}
+		if delay > retryAfterCutoff {
+			// Not worth waiting for
+			return resp, nil
+		}
+		if hasBody && req.GetBody == nil {
+			// The body was consumed and can't be sent again
+			return resp, nil
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		log.Printf("Retrying %s %s in %s after %s (retry %d of %d)",
+			req.Method, req.URL.Path, delay.Round(time.Millisecond), resp.Status, attempt, maxRetries)
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+
+		attemptReq = req.Clone(req.Context())
+		if hasBody {
+			attemptReq.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// The wait Jira asks for with the Retry-After header, given in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date), true
+	}
+	return 0, false
+}
+
+// Doubles with each attempt, randomized to between half and all of that
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << uint(attempt-1)
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
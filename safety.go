@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
+)
+
+const defaultMaxIssuesPerExecution = 10
+
+// The error of actions which were never executed, e.g. because the user stopped.
+// They stay queued as they were
+var errNotAttempted = errors.New("Not attempted")
+
+func distinctIssues(actions []IssueAction) int {
+	seen := make(map[string]bool)
+	for _, ia := range actions {
+		seen[ia.issue.ID] = true
+	}
+	return len(seen)
+}
+
+// Splits actions into consecutive chunks touching at most max issues each.
+// Returns the index each chunk ends at
+func chunkActions(actions []IssueAction, max int) []int {
+	ends := make([]int, 0)
+	seen := make(map[string]bool)
+	for i, ia := range actions {
+		if !seen[ia.issue.ID] && len(seen) == max {
+			ends = append(ends, i)
+			seen = make(map[string]bool)
+		}
+		seen[ia.issue.ID] = true
+	}
+	return append(ends, len(actions))
+}
+
+const (
+	safetyOverride = iota
+	safetyChunks
+	safetyAbort
+)
+
+// Limits how many issues an execution may touch.
+// Returns where the chunks of the execution end, which need confirming in between
+func (e *ExecutorService) checkSafety(actions []IssueAction) ([]int, error) {
+	count := distinctIssues(actions)
+	if count <= e.maxIssues {
+		return []int{len(actions)}, nil
+	}
+
+	fmt.Printf("This execution touches %d issues, more than the limit of %d\n", count, e.maxIssues)
+	p := promptui.Select{
+		Label: "How to proceed",
+		Items: []string{
+			fmt.Sprintf("Execute all %d issues at once", count),
+			fmt.Sprintf("Execute in chunks of %d issues", e.maxIssues),
+			"Abort",
+		},
+		Size: 10,
+	}
+	tty, err := openTty()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open terminal")
+	}
+	if tty != nil {
+		defer tty.Close()
+		p.Stdin = tty
+	}
+	choice, _, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	switch choice {
+	case safetyOverride:
+		typed, err := e.menuService.Prompt(fmt.Sprintf("Type %d to confirm", count), "")
+		if err != nil {
+			return nil, err
+		}
+		if typed != strconv.Itoa(count) {
+			return nil, errors.New("Count didn't match")
+		}
+		return []int{len(actions)}, nil
+	case safetyChunks:
+		return chunkActions(actions, e.maxIssues), nil
+	default:
+		return nil, CancelError()
+	}
+}
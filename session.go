@@ -0,0 +1,312 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
+)
+
+const (
+	sessionsDir        = "sessions"
+	defaultSessionName = "default"
+)
+
+var sessionNameRegexp = regexp.MustCompile(`^[\w.-]+$`)
+
+// What is saved of a workbench. Issues are kept as keys and fetched again on resume
+type Session struct {
+	Name        string              `json:"name"`
+	Saved       time.Time           `json:"saved"`
+	Working     []string            `json:"working"`
+	Selection   []string            `json:"selection"`
+	ActionBases map[int]ActionSpec  `json:"actionBases"`
+	Assigned    []SessionAssignment `json:"assigned"`
+	ActionId    int                 `json:"actionId"`
+	IdIdx       int                 `json:"idIdx"`
+}
+
+type SessionAssignment struct {
+	ActionId int    `json:"actionId"`
+	Key      string `json:"key"`
+	// The error of the last failed attempt, if any
+	Error    string `json:"error,omitempty"`
+	Attempts int    `json:"attempts,omitempty"`
+}
+
+func (s *Session) Format() string {
+	return fmt.Sprintf("%s (%d issues, %d queued, saved %s)",
+		s.Name, len(s.Working), len(s.Assigned), s.Saved.Format("Mon Jan 2 15:04"))
+}
+
+func sessionFile(name string) string {
+	return filepath.Join(sessionsDir, name+".json")
+}
+
+func actionSpecOf(actionBase IssueActionBase) ActionSpec {
+	return append(ActionSpec{actionBase.Key()}, actionBase.ToParams()...)
+}
+
+func newSession(name string, w *Workbench) *Session {
+	session := &Session{
+		Name:        name,
+		Saved:       time.Now(),
+		Working:     make([]string, len(w.working)),
+		Selection:   make([]string, 0, len(w.selection)),
+		ActionBases: make(map[int]ActionSpec),
+		Assigned:    make([]SessionAssignment, len(w.assigned)),
+		ActionId:    w.actionId,
+		IdIdx:       w._idIdx,
+	}
+	for i, issue := range w.working {
+		session.Working[i] = issue.Key
+	}
+	for _, issue := range w.Selected() {
+		session.Selection = append(session.Selection, issue.Key)
+	}
+	for id, actionBase := range w.actionBases {
+		session.ActionBases[id] = actionSpecOf(actionBase)
+	}
+	for i, assigned := range w.assigned {
+		session.Assigned[i] = SessionAssignment{assigned.actionId, assigned.issue.Key, "", assigned.attempts}
+		if assigned.Failed() {
+			session.Assigned[i].Error = assigned.lastErr.Error()
+		}
+	}
+	return session
+}
+
+// Saves and restores named workbench sessions
+// under $XDG_STATE_HOME/gojira-cli/sessions
+type SessionService struct {
+	issueFetcher      *IssueFetcher
+	actionBaseService *ActionBaseService
+	menuService       *MenuService
+	// The session being saved to. Nothing is saved while empty
+	name string
+}
+
+func (s *SessionService) List() ([]*Session, error) {
+	files, err := listState(sessionsDir)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*Session, 0, len(files))
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".json") {
+			continue
+		}
+		session := &Session{}
+		_, err := loadState(filepath.Join(sessionsDir, file), session)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	// Most recent first
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Saved.After(sessions[j].Saved) })
+	return sessions, nil
+}
+
+func (s *SessionService) Delete(name string) error {
+	if !sessionNameRegexp.MatchString(name) {
+		return errors.Errorf("Invalid session name %s", name)
+	}
+	if name == s.name {
+		s.name = ""
+	}
+	return removeState(sessionFile(name))
+}
+
+// Saves the workbench to the current session
+func (s *SessionService) Save(w *Workbench) error {
+	if s.name == "" {
+		return nil
+	}
+	return saveState(sessionFile(s.name), newSession(s.name, w))
+}
+
+// Makes name the current session, loading it into the workbench if it exists
+func (s *SessionService) Open(name string, w *Workbench) error {
+	if !sessionNameRegexp.MatchString(name) {
+		return errors.Errorf("Invalid session name %s", name)
+	}
+	session := &Session{}
+	found, err := loadState(sessionFile(name), session)
+	if err != nil {
+		return err
+	}
+	if found {
+		err = s.restore(session, w)
+		if err != nil {
+			return err
+		}
+	}
+	s.name = name
+	return nil
+}
+
+func (s *SessionService) restore(session *Session, w *Workbench) error {
+	// Fetch everything in one go, assigned issues need not be in the working set
+	keys := append([]string{}, session.Working...)
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		seen[key] = true
+	}
+	for _, assigned := range session.Assigned {
+		if _, prs := seen[assigned.Key]; !prs {
+			seen[assigned.Key] = true
+			keys = append(keys, assigned.Key)
+		}
+	}
+
+	byKey := make(map[string]jira.Issue)
+	if len(keys) > 0 {
+		fmt.Printf("Fetching %d issues for session %s\n", len(keys), session.Name)
+		issues, missing, err := s.issueFetcher.FetchByKeys(keys)
+		if err != nil {
+			return err
+		}
+		for _, key := range missing {
+			fmt.Printf("Issue no longer exists: %s\n", key)
+		}
+		for _, issue := range issues {
+			byKey[issue.Key] = issue
+		}
+	}
+
+	w.Reset()
+	for id, spec := range session.ActionBases {
+		if len(spec) == 0 {
+			continue
+		}
+		actionBase, err := s.actionBaseService.BuildActionParams(spec[0], spec[1:])
+		if err != nil {
+			fmt.Printf("Dropping action %s: %s\n", strings.Join(spec, " "), err)
+			continue
+		}
+		w.actionBases[id] = actionBase
+	}
+	for _, key := range session.Working {
+		if issue, prs := byKey[key]; prs {
+			w.working = append(w.working, issue)
+		}
+	}
+	for _, key := range session.Selection {
+		if issue, prs := byKey[key]; prs {
+			w.selection[issue.ID] = true
+		}
+	}
+	for _, assigned := range session.Assigned {
+		issue, issuePrs := byKey[assigned.Key]
+		_, actionPrs := w.actionBases[assigned.ActionId]
+		if issuePrs && actionPrs {
+			var lastErr error
+			if assigned.Error != "" {
+				lastErr = errors.New(assigned.Error)
+			}
+			w.assigned = append(w.assigned, IssueAssignment{assigned.ActionId, issue, lastErr, assigned.Attempts})
+		}
+	}
+	if _, prs := w.actionBases[session.ActionId]; prs {
+		w.actionId = session.ActionId
+	}
+	w._idIdx = session.IdIdx
+	// The resumed state is the starting point
+	w.ClearHistory()
+	return nil
+}
+
+// Lets the user resume, start or delete sessions at startup
+func (s *SessionService) SelectInteractive(w *Workbench) error {
+	for {
+		sessions, err := s.List()
+		if err != nil {
+			return err
+		}
+		if len(sessions) == 0 {
+			return s.Open(defaultSessionName, w)
+		}
+
+		items := []string{"New session"}
+		for _, session := range sessions {
+			items = append(items, "Resume "+session.Format())
+		}
+		items = append(items, "Delete a session")
+
+		p := promptui.Select{
+			Label: "Choose a session",
+			Items: items,
+			Size:  10,
+		}
+		cursor, _, err := p.RunCursorAt(1, 0)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case cursor == 0:
+			name, err := s.menuService.Prompt("Session name", time.Now().Format("2006-01-02"))
+			if err != nil {
+				return err
+			}
+			return s.Open(name, w)
+		case cursor <= len(sessions):
+			return s.Open(sessions[cursor-1].Name, w)
+		default:
+			p := promptui.Select{
+				Label: "Delete which session",
+				Items: items[1 : len(items)-1],
+				Size:  10,
+			}
+			idx, _, err := p.Run()
+			if err != nil {
+				continue
+			}
+			err = s.Delete(sessions[idx].Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func NewSessionService(
+	issueFetcher *IssueFetcher,
+	actionBaseService *ActionBaseService,
+	menuService *MenuService,
+) *SessionService {
+	return &SessionService{
+		issueFetcher:      issueFetcher,
+		actionBaseService: actionBaseService,
+		menuService:       menuService,
+	}
+}
+
+// Lists the saved sessions, or deletes one
+func RunSessions(app *App, command string, name string) error {
+	switch command {
+	case "", "list":
+		sessions, err := app.sessionService.List()
+		if err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			fmt.Println(session.Format())
+		}
+		return nil
+	case "rm":
+		if name == "" {
+			return errors.New("Please provide the name of the session to delete")
+		}
+		return app.sessionService.Delete(name)
+	default:
+		return errors.Errorf("Unknown sessions command %s", command)
+	}
+}
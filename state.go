@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/adrg/xdg"
+	"github.com/pkg/errors"
+)
+
+// State is kept in json files under $XDG_STATE_HOME/gojira-cli so it survives restarts
+
+func stateFile(relPath string) (string, error) {
+	return xdg.StateFile(filepath.Join("gojira-cli", relPath))
+}
+
+// Lists the names of the state files in a directory, which may not exist yet
+func listState(relDir string) ([]string, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(xdg.StateHome, "gojira-cli", relDir))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Loads the state file into v. Returns false if there is no such state
+func loadState(relPath string, v interface{}) (bool, error) {
+	filePath, err := stateFile(relPath)
+	if err != nil {
+		return false, err
+	}
+	bs, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	err = json.Unmarshal(bs, v)
+	if err != nil {
+		return false, errors.Wrapf(err, "Failed to parse %s", filePath)
+	}
+	return true, nil
+}
+
+// Replaces the state file with v, never leaving a partially written file behind
+func saveState(relPath string, v interface{}) error {
+	filePath, err := stateFile(relPath)
+	if err != nil {
+		return err
+	}
+	bs, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filePath + ".tmp"
+	err = ioutil.WriteFile(tmp, bs, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "Error writing %s", tmp)
+	}
+	return os.Rename(tmp, filePath)
+}
+
+func removeState(relPath string) error {
+	filePath, err := stateFile(relPath)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
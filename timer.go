@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	timerState         = "timer.json"
+	defaultGranularity = 15 * time.Minute
+)
+
+// A running worklog timer
+type Timer struct {
+	Key     string    `json:"key"`
+	Started time.Time `json:"started"`
+}
+
+func (t *Timer) Elapsed() time.Duration {
+	return time.Since(t.Started)
+}
+
+// Times work on an issue, logging it when stopped.
+// The running timer is kept in a state file so it survives terminal restarts
+type TimerService struct {
+	granularity    time.Duration
+	worklogService *WorklogService
+	menuService    *MenuService
+}
+
+// Returns nil if no timer is running
+func (s *TimerService) Running() (*Timer, error) {
+	timer := &Timer{}
+	found, err := loadState(timerState, timer)
+	if err != nil || !found {
+		return nil, err
+	}
+	return timer, nil
+}
+
+// Starts a timer for the issue, offering to stop the one already running
+func (s *TimerService) Start(key string) error {
+	running, err := s.Running()
+	if err != nil {
+		return err
+	}
+	if running != nil {
+		prompt := fmt.Sprintf("Timer for %s has been running for %s. Stop it and log the work",
+			running.Key, FormatWorkDuration(running.Elapsed()))
+		if !s.menuService.Confirm(prompt) {
+			return CancelError()
+		}
+		err = s.Stop("")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = saveState(timerState, &Timer{Key: key, Started: time.Now()})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Started timer for %s\n", key)
+	return nil
+}
+
+// Stops the running timer and logs the elapsed time, rounded to the configured granularity
+func (s *TimerService) Stop(comment string) error {
+	running, err := s.Running()
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		return errors.New("No timer is running")
+	}
+
+	spent := roundWorkDuration(running.Elapsed(), s.granularity)
+	err = s.worklogService.LogWork(running.Key, spent, running.Started, comment)
+	if err != nil {
+		// Keep the timer so the work isn't lost
+		return err
+	}
+	return removeState(timerState)
+}
+
+func (s *TimerService) Status() error {
+	running, err := s.Running()
+	if err != nil {
+		return err
+	}
+	if running == nil {
+		fmt.Println("No timer is running")
+		return nil
+	}
+	fmt.Printf("%s: %s (since %s)\n", running.Key, FormatWorkDuration(running.Elapsed()), running.Started.Format(time.Kitchen))
+	return nil
+}
+
+func NewTimerService(
+	config *TimerConfig,
+	worklogService *WorklogService,
+	menuService *MenuService,
+) *TimerService {
+	granularity := defaultGranularity
+	if config != nil && config.Granularity != "" {
+		var err error
+		granularity, err = ParseWorkDuration(config.Granularity)
+		if err != nil {
+			fmt.Printf("Ignoring timer granularity: %s\n", err)
+			granularity = defaultGranularity
+		}
+	}
+	return &TimerService{
+		granularity,
+		worklogService,
+		menuService,
+	}
+}
+
+func RunTimer(app *App, command string, key string, comment string) error {
+	svc := app.timerService
+	switch command {
+	case "", "status":
+		return svc.Status()
+	case "start":
+		if key == "" {
+			return errors.New("Please provide an issue key to start a timer for")
+		}
+		// Worklog targets work here too
+		key, err := app.worklogService.ResolveTarget(key)
+		if err != nil {
+			return err
+		}
+		return svc.Start(key)
+	case "stop":
+		return svc.Stop(comment)
+	default:
+		return errors.Errorf("Unknown timer command %s", command)
+	}
+}
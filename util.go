@@ -2,11 +2,12 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/andygrunwald/go-jira"
 	"github.com/pkg/errors"
@@ -62,6 +63,19 @@ func canonicalAction(action IssueActionBase) string {
 	return action.Key() + " " + strings.Join(action.ToParams(), " ")
 }
 
+// Returns the controlling terminal if stdin is not one (e.g. it was piped),
+// or nil if stdin can be used as is
+func openTty() (*os.File, error) {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&os.ModeCharDevice != 0 {
+		return nil, nil
+	}
+	return os.Open("/dev/tty")
+}
+
 func CancelError() error {
 	return errors.New("Cancelled")
 }
@@ -70,16 +84,75 @@ func IsCancelError(err error) bool {
 	return strings.Index(err.Error(), "Cancelled") >= 0
 }
 
-func NewRateLimiter(rate time.Duration, burstLimit int) chan time.Time {
-	tick := time.NewTicker(rate)
-	throttle := make(chan time.Time, burstLimit)
-	go func() {
-		for t := range tick.C {
-			select {
-			case throttle <- t:
-			default:
-			}
-		} // does not exit after tick.Stop()
-	}()
-	return throttle
+// Prints the text, through $PAGER (or less) if it doesn't fit on the screen
+func pageOutput(text string) error {
+	if strings.Count(text, "\n") < terminalRows() {
+		fmt.Print(text)
+		return nil
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			// Pager not available, so just print it
+			fmt.Print(text)
+			return nil
+		}
+		return errors.Wrap(err, "Pager failed")
+	}
+	return nil
+}
+
+// Height of the terminal, falling back to 24 rows if it can't be determined
+func terminalRows() int {
+	if rows, err := strconv.Atoi(os.Getenv("LINES")); err == nil && rows > 0 {
+		return rows
+	}
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err == nil {
+		var rows, cols int
+		if _, err := fmt.Sscan(string(out), &rows, &cols); err == nil && rows > 0 {
+			return rows
+		}
+	}
+	return 24
+}
+
+// Reads a single key press from the terminal without waiting for Enter
+func readKey() (byte, error) {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return 0, err
+	}
+	defer tty.Close()
+
+	stty := func(args ...string) (string, error) {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = tty
+		out, err := cmd.Output()
+		return strings.TrimSpace(string(out)), err
+	}
+	state, err := stty("-g")
+	if err != nil {
+		return 0, err
+	}
+	_, err = stty("-icanon", "-echo", "-isig", "min", "1")
+	if err != nil {
+		return 0, err
+	}
+	defer stty(state)
+
+	key := make([]byte, 1)
+	_, err = tty.Read(key)
+	return key[0], err
 }
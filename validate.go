@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// Implemented by actions which can check against Jira that they would succeed,
+// without changing anything
+type ValidatableAction interface {
+	Validate(issue jira.Issue, v *Validator) error
+}
+
+// The permissions validated actions may need, fetched together for each issue
+var validatedPermissions = []string{
+	"ADD_COMMENTS",
+	"EDIT_ISSUES",
+	"ASSIGN_ISSUES",
+	"LINK_ISSUES",
+	"TRANSITION_ISSUES",
+	"WORK_ON_ISSUES",
+}
+
+// Runs the read-only checks of actions, caching what several of them need
+type Validator struct {
+	client      *jira.Client
+	permissions map[string]map[string]bool
+	editMeta    map[string]map[string]interface{}
+	linkTypes   []jira.IssueLinkType
+}
+
+func NewValidator(client *jira.Client) *Validator {
+	return &Validator{
+		client:      client,
+		permissions: make(map[string]map[string]bool),
+		editMeta:    make(map[string]map[string]interface{}),
+	}
+}
+
+type myPermissions struct {
+	Permissions map[string]struct {
+		HavePermission bool `json:"havePermission"`
+	} `json:"permissions"`
+}
+
+// Fails unless the current user has the permission on the issue, e.g. EDIT_ISSUES
+func (v *Validator) RequirePermission(issue jira.Issue, permission string) error {
+	have, prs := v.permissions[issue.ID]
+	if !prs {
+		query := url.Values{}
+		query.Set("issueId", issue.ID)
+		query.Set("permissions", strings.Join(validatedPermissions, ","))
+		req, err := v.client.NewRequest("GET", "rest/api/2/mypermissions?"+query.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		result := &myPermissions{}
+		resp, err := v.client.Do(req, result)
+		LogHttpResponse(resp)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get permissions")
+		}
+		have = make(map[string]bool)
+		for key, p := range result.Permissions {
+			have[key] = p.HavePermission
+		}
+		v.permissions[issue.ID] = have
+	}
+	if !have[permission] {
+		return errors.Errorf("Missing permission %s on %s", permission, issue.Key)
+	}
+	return nil
+}
+
+// Fails unless the field can be edited on the issue.
+// If values are given they must be among the field's allowed values, if it has any
+func (v *Validator) RequireEditable(issue jira.Issue, field string, values ...string) error {
+	fields, prs := v.editMeta[issue.ID]
+	if !prs {
+		meta, resp, err := v.client.Issue.GetEditMeta(&jira.Issue{ID: issue.ID, Key: issue.Key})
+		LogHttpResponse(resp)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get editmeta")
+		}
+		fields = meta.Fields
+		v.editMeta[issue.ID] = fields
+	}
+
+	fieldMeta, prs := fields[field]
+	if !prs {
+		return errors.Errorf("Field %s can't be edited on %s", field, issue.Key)
+	}
+	meta, _ := fieldMeta.(map[string]interface{})
+	allowed, _ := meta["allowedValues"].([]interface{})
+	if allowed == nil {
+		return nil
+	}
+	for _, value := range values {
+		found := false
+		for _, a := range allowed {
+			allowedValue, _ := a.(map[string]interface{})
+			if name, _ := allowedValue["name"].(string); name == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errors.Errorf("%s is not an allowed value of %s on %s", value, field, issue.Key)
+		}
+	}
+	return nil
+}
+
+// Fails unless the user exists and can be assigned the issue.
+// The user may be given by account ID, see accountIdPrefix
+func (v *Validator) RequireAssignable(issue jira.Issue, userName string) error {
+	query := url.Values{}
+	query.Set("issueKey", issue.Key)
+	if user := userOf(userName); user.AccountID != "" {
+		query.Set("accountId", user.AccountID)
+	} else {
+		query.Set("username", userName)
+	}
+	req, err := v.client.NewRequest("GET", "rest/api/2/user/assignable/search?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	users := make([]jira.User, 0)
+	resp, err := v.client.Do(req, &users)
+	LogHttpResponse(resp)
+	if err != nil {
+		return errors.Wrap(err, "Failed to search assignable users")
+	}
+	for _, user := range users {
+		if isUser(&user, userName) {
+			return nil
+		}
+	}
+	return errors.Errorf("%s can't be assigned %s", userName, issue.Key)
+}
+
+// Fails unless the issue can currently be transitioned to status
+func (v *Validator) RequireTransition(issue jira.Issue, status string) error {
+	transitions, resp, err := v.client.Issue.GetTransitions(issue.ID)
+	LogHttpResponse(resp)
+	if err != nil {
+		return err
+	}
+	_, err = findTransition(transitions, status)
+	return err
+}
+
+func (v *Validator) RequireLinkType(name string) error {
+	if v.linkTypes == nil {
+		linkTypes, resp, err := v.client.IssueLinkType.GetList()
+		LogHttpResponse(resp)
+		if err != nil {
+			return errors.Wrap(err, "Failed to get link types")
+		}
+		v.linkTypes = linkTypes
+	}
+	for _, linkType := range v.linkTypes {
+		if linkType.Name == name {
+			return nil
+		}
+	}
+	return errors.Errorf("No link type %s", name)
+}
+
+// Action checks
+
+func (a AddCommentAction) Validate(issue jira.Issue, v *Validator) error {
+	return v.RequirePermission(issue, "ADD_COMMENTS")
+}
+
+func (a AddLabelAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "EDIT_ISSUES"); err != nil {
+		return err
+	}
+	return v.RequireEditable(issue, "labels")
+}
+
+func (a RemoveLabelAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "EDIT_ISSUES"); err != nil {
+		return err
+	}
+	return v.RequireEditable(issue, "labels")
+}
+
+func (a AssignUserAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "ASSIGN_ISSUES"); err != nil {
+		return err
+	}
+	if a.UserName == "" {
+		return nil
+	}
+	return v.RequireAssignable(issue, a.UserName)
+}
+
+func (a RelateOneAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "LINK_ISSUES"); err != nil {
+		return err
+	}
+	return v.RequireLinkType(a.IssueLinkType.Name)
+}
+
+func (a TransitionAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "TRANSITION_ISSUES"); err != nil {
+		return err
+	}
+	if issue.Fields != nil && issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, a.Status) {
+		return nil
+	}
+	return v.RequireTransition(issue, a.Status)
+}
+
+func (a SetFixVersionAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "EDIT_ISSUES"); err != nil {
+		return err
+	}
+	return v.RequireEditable(issue, "fixVersions", a.Version)
+}
+
+func (a RemoveFixVersionAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "EDIT_ISSUES"); err != nil {
+		return err
+	}
+	return v.RequireEditable(issue, "fixVersions")
+}
+
+func (a AddToSprintAction) Validate(issue jira.Issue, v *Validator) error {
+	if err := v.RequirePermission(issue, "EDIT_ISSUES"); err != nil {
+		return err
+	}
+	_, err := a.resolveSprint(issue, v.client)
+	return err
+}
+
+func (a LogWorkAction) Validate(issue jira.Issue, v *Validator) error {
+	if _, err := ParseWorkDuration(a.Spent); err != nil {
+		return err
+	}
+	return v.RequirePermission(issue, "WORK_ON_ISSUES")
+}
+
+// Checks each action against Jira without changing anything, and reports the result of each.
+// Actions which can't be checked count as valid
+func (e *ExecutorService) Validate(actions []IssueAction) []error {
+	errs := make([]error, len(actions))
+	client, err := e.jiraClientFactory.GetClient()
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	validator := NewValidator(client)
+
+	for i, ia := range actions {
+		formatter := IssueActionFormatter{ia}
+		validatable, ok := ia.action.(ValidatableAction)
+		if !ok {
+			fmt.Println("Not checked: " + formatter.Format())
+			continue
+		}
+		errs[i] = validatable.Validate(ia.issue, validator)
+		if errs[i] != nil {
+			fmt.Printf("INVALID: %s\n    %s\n", formatter.Format(), errs[i].Error())
+		} else {
+			fmt.Println("OK: " + formatter.Format())
+		}
+	}
+	return errs
+}
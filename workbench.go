@@ -16,13 +16,21 @@ type Workbench struct {
 	// If assigning while 0, a new action will need to be constructed
 	actionId int
 	_idIdx   int
+
+	history workbenchHistory
 }
 
 type IssueAssignment struct {
 	actionId int
 	issue    jira.Issue
+	// Set if the last execution of the assignment failed
+	lastErr error
+	// How many times the assignment has been executed
+	attempts int
 }
 
+func (a IssueAssignment) Failed() bool { return a.lastErr != nil }
+
 func (w *Workbench) Format() string {
 	var actionDesc string
 	actionBase, prs := w.actionBases[w.actionId]
@@ -35,9 +43,9 @@ func (w *Workbench) Format() string {
 
 	queue := w.Queue()
 	queueFmt := ""
-	for _, ia := range queue {
+	for i, ia := range queue {
 		formatter := IssueActionFormatter{ia}
-		queueFmt = queueFmt + formatter.Format() + "\n"
+		queueFmt = queueFmt + formatter.Format() + failedMark(w.assigned[i]) + "\n"
 	}
 
 	return fmt.Sprintf(`
@@ -47,8 +55,9 @@ Selected: %d
 Completed: %d
 Actions: %d
 Queued: %d
+Failed: %d
 %s
-`, actionDesc, len(w.working), len(w.selection), len(w.completed), len(w.actionBases), len(queue), queueFmt)
+`, actionDesc, len(w.working), len(w.selection), len(w.completed), len(w.actionBases), len(queue), len(w.Failed()), queueFmt)
 
 }
 
@@ -70,18 +79,65 @@ func (w *Workbench) dupeAssignedChecker() map[string]bool {
 }
 
 func (w *Workbench) AddActionBase(actionBase IssueActionBase) (int, error) {
+	if err := w.checkDupeAction(actionBase); err != nil {
+		return 0, err
+	}
+	w.record()
+	return w.addActionBase(actionBase), nil
+}
+
+func (w *Workbench) checkDupeAction(actionBase IssueActionBase) error {
 	checker := w.dupeActionChecker()
 	if _, prs := checker[canonicalAction(actionBase)]; prs {
-		return 0, errors.New("Action already exists")
+		return errors.New("Action already exists")
 	}
+	return nil
+}
+
+// Adds without recording, for changes which record themselves
+func (w *Workbench) addActionBase(actionBase IssueActionBase) int {
 	w._idIdx = w._idIdx + 1
 	w.actionBases[w._idIdx] = actionBase
-	return w._idIdx, nil
+	return w._idIdx
+}
+
+// Replaces the actionBase, moving its assignments to the replacement.
+// Returns the id of the replacement
+func (w *Workbench) ReplaceActionBase(actionId int, actionBase IssueActionBase) (int, error) {
+	old, prs := w.actionBases[actionId]
+	if !prs {
+		return 0, errors.New("No such action")
+	}
+	if canonicalAction(old) == canonicalAction(actionBase) {
+		return actionId, nil
+	}
+
+	if err := w.checkDupeAction(actionBase); err != nil {
+		return 0, err
+	}
+	w.record()
+	newId := w.addActionBase(actionBase)
+	delete(w.actionBases, actionId)
+
+	assigned := make([]IssueAssignment, len(w.assigned))
+	for i, v := range w.assigned {
+		if v.actionId == actionId {
+			v.actionId = newId
+		}
+		assigned[i] = v
+	}
+	w.assigned = assigned
+
+	if w.actionId == actionId {
+		w.actionId = newId
+	}
+	return newId, nil
 }
 
 // Deletes the actionBase
 // Invalidates assigned
 func (w *Workbench) RemoveActionBase(actionId int) {
+	w.record()
 	delete(w.actionBases, actionId)
 	assigned := make([]IssueAssignment, 0)
 	for _, v := range w.assigned {
@@ -111,21 +167,35 @@ func (w *Workbench) Assign(issue jira.Issue) {
 	if _, prs := checker[issue.ID+" "+canonicalAction(action)]; prs {
 		return
 	}
-	w.assigned = append(w.assigned, IssueAssignment{w.actionId, issue})
+	w.record()
+	w.assigned = append(w.assigned, IssueAssignment{w.actionId, issue, nil, 0})
 }
 
 func (w *Workbench) AssignSelected() {
 	if w.actionId == 0 {
 		panic("No action to assign to")
 	}
-	for _, issue := range w.Selected() {
-		w.Assign(issue)
+	w.Batch(func() error {
+		for _, issue := range w.Selected() {
+			w.Assign(issue)
+		}
+		return nil
+	})
+}
+
+// Sets the action that issues get assigned to
+func (w *Workbench) SetAction(actionId int) {
+	if actionId == w.actionId {
+		return
 	}
+	w.record()
+	w.actionId = actionId
 }
 
 // Removes selected issues from workings
 // The entire selection is invalidated / cleared
 func (w *Workbench) RemoveSelected() {
+	w.record()
 	newWorking := make([]jira.Issue, 0, len(w.working)-len(w.selection))
 
 	for _, issue := range w.working {
@@ -136,31 +206,96 @@ func (w *Workbench) RemoveSelected() {
 
 	w.working = newWorking
 
-	w.ClearSelection()
+	w.selection = make(map[string]bool)
 
 }
 
+// Removes the issues with the given IDs from working, and from the selection
+func (w *Workbench) RemoveIssues(ids map[string]bool) {
+	w.record()
+	newWorking := make([]jira.Issue, 0, len(w.working))
+	for _, issue := range w.working {
+		if !ids[issue.ID] {
+			newWorking = append(newWorking, issue)
+		}
+	}
+	w.working = newWorking
+
+	selection := make(map[string]bool)
+	for id := range w.selection {
+		if !ids[id] {
+			selection[id] = true
+		}
+	}
+	w.selection = selection
+}
+
 func (w *Workbench) SelectAll() {
-	w.ClearSelection()
+	w.record()
+	w.selection = make(map[string]bool)
 	for _, issue := range w.working {
 		w.selection[issue.ID] = true
 	}
 }
 func (w *Workbench) Select(workingIdxs []int) {
-	w.ClearSelection()
+	w.record()
+	w.selection = make(map[string]bool)
 	for _, idx := range workingIdxs {
 		w.selection[w.working[idx].ID] = true
 	}
 }
 
 func (w *Workbench) ClearSelection() {
+	if len(w.selection) == 0 {
+		return
+	}
+	w.record()
 	w.selection = make(map[string]bool)
 }
 
 func (w *Workbench) AddIssues(issues []jira.Issue) {
+	w.record()
 	w.working = mergeJiraIssues(issues, w.working)
 }
 
+// Assignments whose last execution failed
+func (w *Workbench) Failed() []IssueAssignment {
+	failed := make([]IssueAssignment, 0)
+	for _, assigned := range w.assigned {
+		if assigned.Failed() {
+			failed = append(failed, assigned)
+		}
+	}
+	return failed
+}
+
+// Replaces the loaded copies of the issues with the current ones, matched by ID.
+// Slices are replaced rather than modified since history shares them
+func (w *Workbench) RefreshIssues(current []jira.Issue) {
+	byId := make(map[string]jira.Issue, len(current))
+	for _, issue := range current {
+		byId[issue.ID] = issue
+	}
+
+	working := make([]jira.Issue, len(w.working))
+	for i, issue := range w.working {
+		if fresh, prs := byId[issue.ID]; prs {
+			issue = fresh
+		}
+		working[i] = issue
+	}
+	w.working = working
+
+	assigned := make([]IssueAssignment, len(w.assigned))
+	for i, v := range w.assigned {
+		if fresh, prs := byId[v.issue.ID]; prs {
+			v.issue = fresh
+		}
+		assigned[i] = v
+	}
+	w.assigned = assigned
+}
+
 func (w *Workbench) Queue() []IssueAction {
 	queue := make([]IssueAction, len(w.assigned))
 	for i, assigned := range w.assigned {
@@ -169,22 +304,70 @@ func (w *Workbench) Queue() []IssueAction {
 	return queue
 }
 
-// Clears queue at every slot where there isn't an error
+// Indexes into the queue of the assignments which failed
+func (w *Workbench) FailedIdxs() []int {
+	idxs := make([]int, 0)
+	for i, assigned := range w.assigned {
+		if assigned.Failed() {
+			idxs = append(idxs, i)
+		}
+	}
+	return idxs
+}
+
+// Removes the assignments which failed from the queue
+func (w *Workbench) DropFailed() {
+	if len(w.FailedIdxs()) == 0 {
+		return
+	}
+	w.record()
+	assigned := make([]IssueAssignment, 0, len(w.assigned))
+	for _, v := range w.assigned {
+		if !v.Failed() {
+			assigned = append(assigned, v)
+		}
+	}
+	w.assigned = assigned
+}
+
+// Clears queue at every slot where there isn't an error.
+// Executed actions can't be undone, so neither can anything before them
 func (w *Workbench) ExecutionResult(errs []error) {
+	idxs := make([]int, len(errs))
+	for i := range errs {
+		idxs[i] = i
+	}
+	w.ExecutionResultFor(idxs, errs)
+}
+
+// Like ExecutionResult, when only the assignments at idxs were executed
+func (w *Workbench) ExecutionResultFor(idxs []int, errs []error) {
+	w.ClearHistory()
+	results := make(map[int]error, len(idxs))
+	for i, idx := range idxs {
+		results[idx] = errs[i]
+	}
+
 	assigned := make([]IssueAssignment, 0)
-	for i, err := range errs {
+	for i, assignment := range w.assigned {
+		err, executed := results[i]
+		if !executed || err == errNotAttempted {
+			assigned = append(assigned, assignment)
+			continue
+		}
+		assignment.attempts++
 		if err != nil {
-			assigned = append(assigned, w.assigned[i])
+			assignment.lastErr = err
+			assigned = append(assigned, assignment)
 		} else {
-			assignment := w.assigned[i]
 			w.completed = append(w.completed, IssueAction{assignment.issue, w.actionBases[assignment.actionId]})
 		}
 	}
 	w.assigned = assigned
-
 }
 
 func (w *Workbench) Reset() {
+	w.record()
 	w.working = make([]jira.Issue, 0)
 	w.selection = make(map[string]bool)
 	w.assigned = make([]IssueAssignment, 0)
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// How many changes can be undone
+const maxUndo = 100
+
+// The undoable state of the workbench.
+// Slices are never modified in place by the workbench, so they are shared rather than copied.
+// Their capacity is limited so appending to them can't clobber a snapshot
+type workbenchSnapshot struct {
+	working     []jira.Issue
+	selection   map[string]bool
+	assigned    []IssueAssignment
+	actionBases map[int]IssueActionBase
+	actionId    int
+	_idIdx      int
+}
+
+type workbenchHistory struct {
+	undo []workbenchSnapshot
+	redo []workbenchSnapshot
+	// Depth of nested batches, and whether the outermost one has recorded its snapshot yet
+	batchDepth    int
+	batchRecorded bool
+}
+
+func (w *Workbench) snapshot() workbenchSnapshot {
+	selection := make(map[string]bool, len(w.selection))
+	for k, v := range w.selection {
+		selection[k] = v
+	}
+	actionBases := make(map[int]IssueActionBase, len(w.actionBases))
+	for k, v := range w.actionBases {
+		actionBases[k] = v
+	}
+	return workbenchSnapshot{
+		working:     w.working[:len(w.working):len(w.working)],
+		selection:   selection,
+		assigned:    w.assigned[:len(w.assigned):len(w.assigned)],
+		actionBases: actionBases,
+		actionId:    w.actionId,
+		_idIdx:      w._idIdx,
+	}
+}
+
+// Restores a snapshot which has been taken off the history,
+// so its maps can be reused as they are
+func (w *Workbench) restore(s workbenchSnapshot) {
+	w.working = s.working
+	w.selection = s.selection
+	w.assigned = s.assigned
+	w.actionBases = s.actionBases
+	w.actionId = s.actionId
+	w._idIdx = s._idIdx
+}
+
+// Records the current state so the change about to be made can be undone.
+// Within a batch only the first change is recorded
+func (w *Workbench) record() {
+	h := &w.history
+	if h.batchDepth > 0 {
+		if h.batchRecorded {
+			return
+		}
+		h.batchRecorded = true
+	}
+	h.undo = append(h.undo, w.snapshot())
+	if len(h.undo) > maxUndo {
+		h.undo = h.undo[len(h.undo)-maxUndo:]
+	}
+	h.redo = nil
+}
+
+// Groups all changes made by fn so they are undone together
+func (w *Workbench) Batch(fn func() error) error {
+	w.history.batchDepth++
+	defer func() {
+		w.history.batchDepth--
+		if w.history.batchDepth == 0 {
+			w.history.batchRecorded = false
+		}
+	}()
+	return fn()
+}
+
+func (w *Workbench) CanUndo() bool { return len(w.history.undo) > 0 }
+func (w *Workbench) CanRedo() bool { return len(w.history.redo) > 0 }
+
+func (w *Workbench) Undo() error {
+	h := &w.history
+	if len(h.undo) == 0 {
+		return errors.New("Nothing to undo")
+	}
+	last := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	h.redo = append(h.redo, w.snapshot())
+	w.restore(last)
+	return nil
+}
+
+func (w *Workbench) Redo() error {
+	h := &w.history
+	if len(h.redo) == 0 {
+		return errors.New("Nothing to redo")
+	}
+	last := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	h.undo = append(h.undo, w.snapshot())
+	w.restore(last)
+	return nil
+}
+
+// Forgets all changes, e.g. once they can no longer be meaningfully undone.
+// Within a batch the next change is recorded again, so changes after this can be undone
+func (w *Workbench) ClearHistory() {
+	w.history.undo = nil
+	w.history.redo = nil
+	w.history.batchRecorded = false
+}
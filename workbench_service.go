@@ -1,6 +1,13 @@
 package cli
 
-import "log"
+import (
+	"fmt"
+	"log"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/manifoldco/promptui"
+	"github.com/pkg/errors"
+)
 
 // Handles all interactive interaction w/ the Workbench
 type WorkbenchService interface {
@@ -33,6 +40,19 @@ type WorkbenchService interface {
 	AddActionInteractive(w *Workbench) error
 
 	Execute(w *Workbench, dryRun bool) error
+
+	// Checks the queue against Jira without changing anything
+	Validate(w *Workbench) error
+
+	// Executes only the assignments which failed last time
+	RetryFailed(w *Workbench) error
+
+	// Adds the issues of another query to working,
+	// or keeps or removes those which are also in it
+	QueryOperationInteractive(w *Workbench) error
+
+	// Writes the queue to a plan file which can be reviewed and applied later
+	ExportPlanInteractive(w *Workbench) error
 }
 
 type defaultWorkbenchService struct {
@@ -40,6 +60,7 @@ type defaultWorkbenchService struct {
 	issueSearchService *IssueSearchService
 	actionBaseService  *ActionBaseService
 	executorService    *ExecutorService
+	issueFetcher       *IssueFetcher
 }
 
 // Interactively remove issues from working
@@ -133,7 +154,7 @@ func (s *defaultWorkbenchService) AssignInteractive(w *Workbench) error {
 	w.AssignSelected()
 
 	if clearAction {
-		w.actionId = 0
+		w.SetAction(0)
 	}
 
 	if clearSelection {
@@ -158,7 +179,7 @@ func (s *defaultWorkbenchService) SelectActionInteractive(w *Workbench) error {
 			return err
 		}
 
-		w.actionId = actionId
+		w.SetAction(actionId)
 		return nil
 	}
 
@@ -168,7 +189,7 @@ func (s *defaultWorkbenchService) SelectActionInteractive(w *Workbench) error {
 		return err
 	}
 
-	w.actionId = actionId
+	w.SetAction(actionId)
 	return nil
 }
 
@@ -183,8 +204,20 @@ func (s *defaultWorkbenchService) RemoveActionInteractive(w *Workbench) error {
 	return nil
 }
 
+// Rebuild an action base interactively, keeping the issues assigned to it
 func (s *defaultWorkbenchService) EditActionInteractive(w *Workbench) error {
-	panic("not impl")
+	actionId, err := s.actionBaseService.SelectAction(w.actionBases)
+	if err != nil {
+		return err
+	}
+
+	actionBase, err := s.actionBaseService.RebuildAction(w.actionBases[actionId])
+	if err != nil {
+		return err
+	}
+
+	_, err = w.ReplaceActionBase(actionId, actionBase)
+	return err
 }
 
 // Construct and Add an action base, also sets the actionId
@@ -199,15 +232,269 @@ func (s *defaultWorkbenchService) AddActionInteractive(w *Workbench) error {
 		return err
 	}
 
-	w.actionId = actionId
+	w.SetAction(actionId)
 	return nil
 }
 
 func (s *defaultWorkbenchService) Execute(w *Workbench, dryRun bool) error {
-	errs := s.executorService.Execute(w.Queue(), dryRun)
-	if !dryRun {
-		w.ExecutionResult(errs)
+	if dryRun {
+		s.executorService.Execute(w.Queue(), true)
+		return nil
+	}
+	idxs := make([]int, len(w.assigned))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	return s.executeIdxs(w, idxs)
+}
+
+func (s *defaultWorkbenchService) Validate(w *Workbench) error {
+	queue := w.Queue()
+	invalid := 0
+	for _, err := range s.executorService.Validate(queue) {
+		if err != nil {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return errors.Errorf("%d of %d queued action(s) are invalid", invalid, len(queue))
+	}
+	return nil
+}
+
+func (s *defaultWorkbenchService) RetryFailed(w *Workbench) error {
+	idxs := w.FailedIdxs()
+	if len(idxs) == 0 {
+		return errors.New("No failed actions to retry")
+	}
+	return s.executeIdxs(w, idxs)
+}
+
+// Refreshes the issues, then executes the assignments at idxs
+func (s *defaultWorkbenchService) executeIdxs(w *Workbench, idxs []int) error {
+	excluded, err := s.refreshQueued(w, idxs)
+	if err != nil {
+		return err
+	}
+
+	queue := w.Queue()
+	executed := make([]int, 0, len(idxs))
+	actions := make([]IssueAction, 0, len(idxs))
+	for _, idx := range idxs {
+		if !excluded[queue[idx].issue.ID] {
+			executed = append(executed, idx)
+			actions = append(actions, queue[idx])
+		}
+	}
+
+	s.executorService.Execute(actions, true)
+	if !s.actionBaseService.menuService.Confirm(fmt.Sprintf("Execute %d action(s)", len(actions))) {
+		return CancelError()
+	}
+	errs := s.executorService.Execute(actions, false)
+	w.ExecutionResultFor(executed, errs)
+
+	done, failed, notAttempted := 0, 0, 0
+	for _, err := range errs {
+		switch err {
+		case nil:
+			done++
+		case errNotAttempted:
+			notAttempted++
+		default:
+			failed++
+		}
+	}
+	fmt.Printf("%d done, %d failed, %d not attempted and still queued\n", done, failed, notAttempted)
+	return nil
+}
+
+const (
+	staleContinue = iota
+	staleExclude
+	staleAbort
+)
+
+// Fetches the issues queued at idxs again in one batched search,
+// so actions see their current state. If any were changed since they were loaded,
+// the user decides whether to continue anyway, or exclude them.
+// Returns the IDs of the excluded issues
+func (s *defaultWorkbenchService) refreshQueued(w *Workbench, idxs []int) (map[string]bool, error) {
+	loaded := make([]jira.Issue, 0, len(idxs))
+	seen := make(map[string]bool)
+	keys := make([]string, 0, len(idxs))
+	for _, idx := range idxs {
+		issue := w.assigned[idx].issue
+		if !seen[issue.ID] {
+			seen[issue.ID] = true
+			loaded = append(loaded, issue)
+			keys = append(keys, issue.Key)
+		}
+	}
+
+	fmt.Printf("Refreshing %d issue(s)\n", len(keys))
+	current, _, err := s.issueFetcher.FetchByKeys(keys)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to refresh issues")
+	}
+	byId := make(map[string]jira.Issue, len(current))
+	for _, issue := range current {
+		byId[issue.ID] = issue
+	}
+
+	stale := make([]StaleIssue, 0)
+	unchanged := make([]jira.Issue, 0, len(current))
+	changed := make([]jira.Issue, 0)
+	for _, issue := range loaded {
+		fresh, prs := byId[issue.ID]
+		if !prs {
+			stale = append(stale, StaleIssue{issue, nil, nil})
+			continue
+		}
+		if updated, fields := issueChanges(issue, fresh); updated {
+			stale = append(stale, StaleIssue{issue, &fresh, fields})
+			changed = append(changed, fresh)
+		} else {
+			unchanged = append(unchanged, fresh)
+		}
+	}
+	// Changed issues keep their loaded copy unless the user continues,
+	// so they are flagged again by the next execution
+	w.RefreshIssues(unchanged)
+
+	excluded := make(map[string]bool)
+	if len(stale) == 0 {
+		return excluded, nil
+	}
+
+	fmt.Printf("%d issue(s) changed since they were loaded:\n", len(stale))
+	for _, v := range stale {
+		fmt.Println("  " + v.Format())
+	}
+	p := promptui.Select{
+		Label: "How to proceed",
+		Items: []string{"Continue with all issues", "Exclude changed issues", "Abort"},
+		Size:  10,
+	}
+	choice, _, err := p.Run()
+	if err != nil {
+		return nil, err
+	}
+	switch choice {
+	case staleContinue:
+		w.RefreshIssues(changed)
+	case staleExclude:
+		for _, v := range stale {
+			excluded[v.Loaded.ID] = true
+		}
+	case staleAbort:
+		return nil, CancelError()
+	}
+	return excluded, nil
+}
+
+const (
+	queryUnion = iota
+	queryIntersect
+	querySubtract
+)
+
+var queryOperations = []string{
+	"Add all issues of a query",
+	"Keep only issues which are also in a query",
+	"Remove issues which are in a query",
+}
+
+func (s *defaultWorkbenchService) QueryOperationInteractive(w *Workbench) error {
+	p := promptui.Select{
+		Label: "Choose an operation",
+		Items: queryOperations,
+		Size:  10,
+	}
+	op, _, err := p.Run()
+	if err != nil {
+		return err
+	}
+
+	jql, err := s.issueSearchService.SelectQueryInteractive()
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	if op != queryUnion {
+		keys = make([]string, len(w.working))
+		for i, issue := range w.working {
+			keys[i] = issue.Key
+		}
+	}
+	issues, err := s.issueFetcher.Search(jql, keys)
+	if err != nil {
+		return err
+	}
+	matched := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		matched[issue.ID] = true
+	}
+
+	working := make(map[string]bool, len(w.working))
+	for _, issue := range w.working {
+		working[issue.ID] = true
+	}
+
+	var (
+		prompt string
+		count  int
+	)
+	remove := make(map[string]bool)
+	switch op {
+	case queryUnion:
+		for id := range matched {
+			if !working[id] {
+				count++
+			}
+		}
+		prompt = fmt.Sprintf("Add %d of %d matching issue(s)", count, len(matched))
+	case queryIntersect, querySubtract:
+		for id := range working {
+			if matched[id] == (op == querySubtract) {
+				remove[id] = true
+			}
+		}
+		count = len(remove)
+		prompt = fmt.Sprintf("Remove %d of %d issue(s)", count, len(w.working))
+	}
+
+	if count == 0 {
+		fmt.Println("Nothing to change")
+		return nil
+	}
+	if !s.actionBaseService.menuService.Confirm(prompt) {
+		return CancelError()
+	}
+
+	if op == queryUnion {
+		w.AddIssues(issues)
+	} else {
+		w.RemoveIssues(remove)
+	}
+	return nil
+}
+
+func (s *defaultWorkbenchService) ExportPlanInteractive(w *Workbench) error {
+	queue := w.Queue()
+	if len(queue) == 0 {
+		return errors.New("Nothing queued")
+	}
+	path, err := s.actionBaseService.menuService.Prompt("Export plan to", "plan.yml")
+	if err != nil {
+		return err
+	}
+	err = WritePlan(path, NewPlan(queue))
+	if err != nil {
+		return err
 	}
+	fmt.Printf("Wrote %d action(s) to %s\n", len(queue), path)
 	return nil
 }
 
@@ -216,11 +503,13 @@ func NewWorkbenchService(
 	issueSearchService *IssueSearchService,
 	actionBaseService *ActionBaseService,
 	executorService *ExecutorService,
+	issueFetcher *IssueFetcher,
 ) WorkbenchService {
 	return &defaultWorkbenchService{
 		issueSelector,
 		issueSearchService,
 		actionBaseService,
 		executorService,
+		issueFetcher,
 	}
 }
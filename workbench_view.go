@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A detailed view of the whole workbench, with issues in the given format.
+// The working set is grouped under headings if groupBy is set
+func (w *Workbench) Detail(formatter IssueFormatter, groupBy string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Working set (%d issues, %d selected):\n", len(w.working), len(w.selection))
+	group := ""
+	for i, idx := range formatter.Order(w.working) {
+		issue := w.working[idx]
+		if groupBy != "" {
+			if g := issueGroup(issue, groupBy); i == 0 || g != group {
+				group = g
+				fmt.Fprintf(&b, "%s:\n", group)
+			}
+		}
+		mark := " "
+		if w.selection[issue.ID] {
+			mark = "*"
+		}
+		fmt.Fprintf(&b, "%s %s\n", mark, formatter.Format(issue))
+	}
+
+	fmt.Fprintf(&b, "\nQueue by action (%d):\n", len(w.assigned))
+	actionIds := make([]int, 0, len(w.actionBases))
+	for actionId := range w.actionBases {
+		actionIds = append(actionIds, actionId)
+	}
+	sort.Ints(actionIds)
+	for _, actionId := range actionIds {
+		current := ""
+		if actionId == w.actionId {
+			current = " (current)"
+		}
+		fmt.Fprintf(&b, "  %s%s\n", WrapFormatter(w.actionBases[actionId]).Format(), current)
+		for _, assigned := range w.assigned {
+			if assigned.actionId == actionId {
+				fmt.Fprintf(&b, "    %s%s\n", formatter.Format(assigned.issue), failedMark(assigned))
+			}
+		}
+	}
+
+	fmt.Fprintf(&b, "\nQueue by issue:\n")
+	issueIds := make([]string, 0)
+	byIssue := make(map[string][]IssueAssignment)
+	for _, assigned := range w.assigned {
+		if _, prs := byIssue[assigned.issue.ID]; !prs {
+			issueIds = append(issueIds, assigned.issue.ID)
+		}
+		byIssue[assigned.issue.ID] = append(byIssue[assigned.issue.ID], assigned)
+	}
+	for _, id := range issueIds {
+		fmt.Fprintf(&b, "  %s\n", formatter.Format(byIssue[id][0].issue))
+		for _, assigned := range byIssue[id] {
+			ia := IssueAction{assigned.issue, w.actionBases[assigned.actionId]}
+			fmt.Fprintf(&b, "    %s%s\n", IssueActionFormatter{ia}.Format(), failedMark(assigned))
+		}
+	}
+
+	fmt.Fprintf(&b, "\nCompleted (%d):\n", len(w.completed))
+	for _, ia := range w.completed {
+		fmt.Fprintf(&b, "  %s\n", IssueActionFormatter{ia}.Format())
+	}
+
+	failed := w.Failed()
+	fmt.Fprintf(&b, "\nFailed (%d):\n", len(failed))
+	for _, assigned := range failed {
+		ia := IssueAction{assigned.issue, w.actionBases[assigned.actionId]}
+		fmt.Fprintf(&b, "  %s\n    after %d attempt(s): %s\n", IssueActionFormatter{ia}.Format(), assigned.attempts, assigned.lastErr.Error())
+	}
+
+	return b.String()
+}
+
+func failedMark(assigned IssueAssignment) string {
+	if assigned.Failed() {
+		return fmt.Sprintf(" (failed, %d attempt(s): %s)", assigned.attempts, assigned.lastErr.Error())
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/pkg/errors"
+)
+
+// Parses work durations the way Jira writes them, e.g. "1h 30m", as well as "1h30m"
+func ParseWorkDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		return 0, errors.Wrapf(err, "Invalid duration %s", s)
+	}
+	if d <= 0 {
+		return 0, errors.Errorf("Duration must be positive: %s", s)
+	}
+	return d, nil
+}
+
+// Formats a work duration the way Jira writes them, e.g. "1h 30m"
+func FormatWorkDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
+
+// Rounds to the nearest multiple of granularity, but never to less than one
+func roundWorkDuration(d time.Duration, granularity time.Duration) time.Duration {
+	if granularity <= 0 {
+		return d
+	}
+	rounded := d.Round(granularity)
+	if rounded < granularity {
+		return granularity
+	}
+	return rounded
+}
+
+func addWorklog(client *jira.Client, issueID string, spent time.Duration, started time.Time, comment string) (*jira.WorklogRecord, error) {
+	startedJira := jira.Time(started)
+	record, resp, err := client.Issue.AddWorklogRecord(issueID, &jira.WorklogRecord{
+		Comment:          comment,
+		Started:          &startedJira,
+		TimeSpentSeconds: int(spent.Round(time.Minute) / time.Second),
+	})
+	LogHttpResponse(resp)
+	return record, err
+}
+
+// Logs work directly, for work which isn't tied to actions in the workbench
+type WorklogService struct {
+	jiraClientFactory *JiraClientFactory
+	targets           map[string]string
+}
+
+// Resolves a configured target name to its issue key, or passes an issue key through
+func (s *WorklogService) ResolveTarget(target string) (string, error) {
+	if key, prs := s.targets[target]; prs {
+		if key == "" {
+			return "", errors.Errorf("No issue configured for worklog target %s", target)
+		}
+		return key, nil
+	}
+	if IsIssueKey(target) {
+		return target, nil
+	}
+	return "", errors.Errorf("%s is neither a worklog target nor an issue key", target)
+}
+
+// Interactively pick one of the configured targets
+func (s *WorklogService) SelectTarget() (string, error) {
+	names := keysFromMap(s.targets)
+	if len(names) == 0 {
+		return "", errors.New("No worklog targets configured")
+	}
+	formatters := make([]Formatter, len(names))
+	for i, name := range names {
+		formatters[i] = StringFormatter(name + " (" + s.targets[name] + ")")
+	}
+	idxs, cancelled, err := FzfSelect(formatters, SelectOptions{
+		Prompt: "Log work against",
+		One:    true,
+	}, 0)
+	if err != nil {
+		return "", err
+	}
+	if cancelled {
+		return "", CancelError()
+	}
+	if len(idxs) != 1 {
+		panic("Expected exactly one")
+	}
+	return names[idxs[0]], nil
+}
+
+func (s *WorklogService) LogWork(key string, spent time.Duration, started time.Time, comment string) error {
+	client, err := s.jiraClientFactory.GetClient()
+	if err != nil {
+		return err
+	}
+	_, err = addWorklog(client, key, spent, started, comment)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to log %s on %s", FormatWorkDuration(spent), key)
+	}
+	fmt.Printf("Logged %s on %s\n", FormatWorkDuration(spent), key)
+	return nil
+}
+
+func NewWorklogService(jiraClientFactory *JiraClientFactory, targets map[string]string) *WorklogService {
+	return &WorklogService{jiraClientFactory, targets}
+}
+
+// Logs work against a target or issue key, ending now.
+// Anything not given is asked for interactively
+func RunLog(app *App, target string, duration string, comment string) error {
+	svc := app.worklogService
+	var err error
+	interactive := target == "" || duration == ""
+	if target == "" {
+		target, err = svc.SelectTarget()
+		if err != nil {
+			return err
+		}
+	}
+	key, err := svc.ResolveTarget(target)
+	if err != nil {
+		return err
+	}
+
+	if duration == "" {
+		duration, err = app.menuService.Prompt("Time spent on "+key+" (e.g. 1h 30m)", "")
+		if err != nil {
+			return err
+		}
+	}
+	spent, err := ParseWorkDuration(duration)
+	if err != nil {
+		return err
+	}
+
+	if interactive && comment == "" {
+		comment, err = app.menuService.Prompt("Work description (optional)", "")
+		if err != nil {
+			return err
+		}
+	}
+
+	return svc.LogWork(key, spent, time.Now().Add(-spent), comment)
+}